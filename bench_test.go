@@ -0,0 +1,228 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/goes-funky/httprouter"
+)
+
+var lookupTests = []struct {
+	name string
+	path string
+}{
+	{name: "static", path: "/users"},
+	{name: "param", path: "/users/42"},
+	{name: "multiple params", path: "/users/42/addresses/7"},
+	{name: "catch-all", path: "/static/css/site.css"},
+}
+
+func newLookupRouter() *httprouter.Router {
+	router := httprouter.New()
+	router.Handler(http.MethodGet, "/users", httprouter.NoopHandler)
+	router.Handler(http.MethodGet, "/users/:id", httprouter.NoopHandler)
+	router.Handler(http.MethodGet, "/users/:id/addresses/:address_id", httprouter.NoopHandler)
+	router.Handler(http.MethodGet, "/static/*path", httprouter.NoopHandler)
+
+	return router
+}
+
+// lookuper is implemented by Router and Compiled.
+type lookuper interface {
+	Lookup(rw http.ResponseWriter, req *http.Request) httprouter.LookupResult
+}
+
+func TestLookupAllocs(t *testing.T) {
+	router := newLookupRouter()
+	lookupers := map[string]lookuper{
+		"tree":     router,
+		"compiled": router.Compile(),
+	}
+
+	for name, l := range lookupers {
+		l := l
+		for _, test := range lookupTests {
+			test := test
+			t.Run(name+" "+test.name, func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, test.path, nil)
+
+				allocs := testing.AllocsPerRun(100, func() {
+					lr := l.Lookup(nil, req)
+					lr.Release()
+				})
+
+				if allocs != 0 {
+					t.Errorf("expected no allocations, got %v", allocs)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	router := newLookupRouter()
+
+	for _, test := range lookupTests {
+		test := test
+		b.Run(test.name, func(b *testing.B) {
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				lr := router.Lookup(nil, req)
+				lr.Release()
+			}
+		})
+	}
+}
+
+type apiLookup struct {
+	name   string
+	method string
+	path   string
+}
+
+func newAPIRouter(routes []apiRoute) *httprouter.Router {
+	router := httprouter.New()
+	for _, route := range routes {
+		router.Handler(route.method, route.path, httprouter.NoopHandler)
+	}
+
+	return router
+}
+
+// benchmarkAPI benchmarks lookups against a router with routes registered, including a
+// lookup of every route with its parameters matching their literal names. The router
+// is compiled if compile is set.
+func benchmarkAPI(b *testing.B, routes []apiRoute, lookups []apiLookup, compile bool) {
+	var router lookuper = newAPIRouter(routes)
+	if compile {
+		router = router.(*httprouter.Router).Compile()
+	}
+
+	for _, lookup := range lookups {
+		lookup := lookup
+		b.Run(lookup.name, func(b *testing.B) {
+			req := httptest.NewRequest(lookup.method, lookup.path, nil)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				lr := router.Lookup(nil, req)
+				lr.Release()
+			}
+		})
+	}
+
+	b.Run("all", func(b *testing.B) {
+		reqs := make([]*http.Request, len(routes))
+		for i, route := range routes {
+			reqs[i] = httptest.NewRequest(route.method, route.path, nil)
+		}
+
+		b.ReportAllocs()
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			for _, req := range reqs {
+				lr := router.Lookup(nil, req)
+				lr.Release()
+			}
+		}
+	})
+}
+
+var githubLookups = []apiLookup{
+	{name: "static", method: http.MethodGet, path: "/user/repos"},
+	{name: "param", method: http.MethodGet, path: "/repos/goes-funky/httprouter/stargazers"},
+	{name: "multiple params", method: http.MethodGet, path: "/legacy/issues/search/goes-funky/httprouter/open/router"},
+	{name: "catch-all", method: http.MethodGet, path: "/repos/goes-funky/httprouter/contents/docs/README.md"},
+	{name: "not found", method: http.MethodGet, path: "/user/repositories/unknown"},
+	{name: "method not allowed", method: http.MethodPost, path: "/users/fry/followers"},
+}
+
+var parseLookups = []apiLookup{
+	{name: "static", method: http.MethodGet, path: "/1/users"},
+	{name: "param", method: http.MethodGet, path: "/1/classes/go"},
+	{name: "multiple params", method: http.MethodGet, path: "/1/classes/go/123456789"},
+	{name: "not found", method: http.MethodGet, path: "/2/users"},
+	{name: "method not allowed", method: http.MethodPatch, path: "/1/users"},
+}
+
+var gplusLookups = []apiLookup{
+	{name: "static", method: http.MethodGet, path: "/people"},
+	{name: "param", method: http.MethodGet, path: "/people/118051310819094153327"},
+	{name: "multiple params", method: http.MethodGet, path: "/people/118051310819094153327/activities/public"},
+	{name: "not found", method: http.MethodGet, path: "/circles"},
+	{name: "method not allowed", method: http.MethodPut, path: "/moments/1"},
+}
+
+func BenchmarkGitHubAPI(b *testing.B) {
+	benchmarkAPI(b, githubAPI, githubLookups, false)
+}
+
+func BenchmarkGitHubAPICompiled(b *testing.B) {
+	benchmarkAPI(b, githubAPI, githubLookups, true)
+}
+
+func BenchmarkParseAPI(b *testing.B) {
+	benchmarkAPI(b, parseAPI, parseLookups, false)
+}
+
+func BenchmarkParseAPICompiled(b *testing.B) {
+	benchmarkAPI(b, parseAPI, parseLookups, true)
+}
+
+func BenchmarkGooglePlusAPI(b *testing.B) {
+	benchmarkAPI(b, gplusAPI, gplusLookups, false)
+}
+
+func BenchmarkGooglePlusAPICompiled(b *testing.B) {
+	benchmarkAPI(b, gplusAPI, gplusLookups, true)
+}
+
+// discardResponseWriter is a minimal http.ResponseWriter, so benchmarks of serving
+// requests don't measure the cost of recording responses.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardResponseWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func (w *discardResponseWriter) WriteHeader(int) {}
+
+func BenchmarkServeHTTP(b *testing.B) {
+	router := newAPIRouter(githubAPI)
+
+	lookups := []apiLookup{
+		{name: "static", method: http.MethodGet, path: "/user/repos"},
+		{name: "param", method: http.MethodGet, path: "/repos/goes-funky/httprouter/stargazers"},
+		{name: "not found", method: http.MethodGet, path: "/user/repositories/unknown"},
+		{name: "method not allowed", method: http.MethodPost, path: "/users/fry/followers"},
+	}
+
+	for _, lookup := range lookups {
+		lookup := lookup
+		b.Run(lookup.name, func(b *testing.B) {
+			req := httptest.NewRequest(lookup.method, lookup.path, nil)
+			w := &discardResponseWriter{header: make(http.Header)}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				router.ServeHTTP(w, req)
+			}
+		})
+	}
+}
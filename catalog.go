@@ -0,0 +1,78 @@
+package httprouter
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// ErrorCode describes an error code declared in an ErrorCatalog.
+type ErrorCode struct {
+	Code    string `json:"code"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// ErrorCatalog is a registry of the error codes of a service with their default status
+// and message, so errors with the same code are consistent. It's safe for concurrent use.
+type ErrorCatalog struct {
+	mu    sync.RWMutex
+	codes map[string]ErrorCode
+}
+
+func NewErrorCatalog() *ErrorCatalog {
+	return &ErrorCatalog{codes: make(map[string]ErrorCode)}
+}
+
+// Register declares code with its default status and message, which defaults to the status text.
+// It panics if code is empty or already registered.
+func (c *ErrorCatalog) Register(code string, status int, message string) {
+	if code == "" {
+		panic("Error code must be non empty")
+	}
+
+	if message == "" {
+		message = http.StatusText(status)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.codes[code]; ok {
+		panic(fmt.Sprintf("Error code %q is already registered", code))
+	}
+
+	c.codes[code] = ErrorCode{Code: code, Status: status, Message: message}
+}
+
+// Error creates an Error with code and its registered status and message, which opts
+// can override. An unregistered code yields an Error with status 500.
+func (c *ErrorCatalog) Error(code string, opts ...ErrorOpt) Error {
+	c.mu.RLock()
+	ec, ok := c.codes[code]
+	c.mu.RUnlock()
+
+	if !ok {
+		return NewError(http.StatusInternalServerError, append([]ErrorOpt{Code(code)}, opts...)...)
+	}
+
+	return NewError(ec.Status, append([]ErrorOpt{Code(code), Message(ec.Message)}, opts...)...)
+}
+
+// Codes returns the registered error codes sorted by code, e.g. to be exported for clients.
+func (c *ErrorCatalog) Codes() []ErrorCode {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	codes := make([]ErrorCode, 0, len(c.codes))
+	for _, ec := range c.codes {
+		codes = append(codes, ec)
+	}
+
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i].Code < codes[j].Code
+	})
+
+	return codes
+}
@@ -0,0 +1,64 @@
+package httprouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestErrorCatalog(t *testing.T) {
+	catalog := httprouter.NewErrorCatalog()
+	catalog.Register("USER_NOT_FOUND", http.StatusNotFound, "User not found")
+	catalog.Register("RATE_LIMITED", http.StatusTooManyRequests, "")
+
+	router := httprouter.New()
+	router.GET("/users/:id", func(w http.ResponseWriter, req *http.Request) error {
+		return catalog.Error("USER_NOT_FOUND", httprouter.Operational())
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status code %d, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	var resp httprouter.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := httprouter.ErrorResponse{Code: "USER_NOT_FOUND", Message: "User not found"}
+	if diff := cmp.Diff(expected, resp); diff != "" {
+		t.Error("unexpected response", diff)
+	}
+
+	if err := catalog.Error("RATE_LIMITED", httprouter.Message("Slow down")); err.Status != http.StatusTooManyRequests || err.Message != "Slow down" {
+		t.Errorf("expected options to override message, got %+v", err)
+	}
+
+	if err := catalog.Error("UNKNOWN"); err.Status != http.StatusInternalServerError || err.Code != "UNKNOWN" {
+		t.Errorf("expected unregistered code to yield status 500, got %+v", err)
+	}
+
+	expectedCodes := []httprouter.ErrorCode{
+		{Code: "RATE_LIMITED", Status: http.StatusTooManyRequests, Message: http.StatusText(http.StatusTooManyRequests)},
+		{Code: "USER_NOT_FOUND", Status: http.StatusNotFound, Message: "User not found"},
+	}
+	if diff := cmp.Diff(expectedCodes, catalog.Codes()); diff != "" {
+		t.Error("unexpected codes", diff)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected duplicate code to panic")
+		}
+	}()
+
+	catalog.Register("USER_NOT_FOUND", http.StatusGone, "")
+}
@@ -0,0 +1,231 @@
+package httprouter
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Compiled is a read-only snapshot of the routes of a Router, compiled into a matcher
+// optimized for lookups. It serves requests like the Router it was compiled from,
+// without the routes registered or removed later.
+type Compiled struct {
+	router *Router
+	table  *table
+}
+
+// Compile returns the current routes of r compiled for faster lookups. Static children
+// are indexed by their first byte rather than scanned, and handlers of standard methods
+// are kept in arrays rather than maps.
+func (r *Router) Compile() *Compiled {
+	t := r.load().clone()
+	t.compiled = compileTree(t.root)
+	for i := range t.hosts {
+		t.hosts[i].compiled = compileTree(t.hosts[i].root)
+	}
+
+	return &Compiled{router: r, table: t}
+}
+
+// ServeHTTP implements http.Handler
+func (c *Compiled) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	lr := c.Lookup(rw, req)
+	c.router.ServeLookupResult(rw, req, lr)
+}
+
+// Lookup looks up the route for req like Router.Lookup.
+func (c *Compiled) Lookup(rw http.ResponseWriter, req *http.Request) LookupResult {
+	return c.router.lookup(c.table, req)
+}
+
+// ServeLookupResult serves req with the result of Lookup like Router.ServeLookupResult.
+func (c *Compiled) ServeLookupResult(rw http.ResponseWriter, req *http.Request, lr LookupResult) {
+	c.router.ServeLookupResult(rw, req, lr)
+}
+
+// cnode is the compiled form of a node, with the node itself kept as leaf if it has handlers.
+type cnode struct {
+	path string
+
+	// Positions in staticChild plus one, indexed by the first byte of the child path
+	// minus indexBase, or zero if there is no such child.
+	indexBase byte
+	indices   []uint16
+
+	staticChild      []*cnode
+	wildcardChildren []*cnode
+	catchAllChild    *cnode
+
+	constraint *constraint
+
+	leaf *node
+	// Handlers of the standard methods, in the order of standardMethods.
+	handlers [9]HandlerFunc
+}
+
+func methodIndex(method string) int {
+	switch method {
+	case http.MethodGet:
+		return 0
+	case http.MethodHead:
+		return 1
+	case http.MethodPost:
+		return 2
+	case http.MethodPut:
+		return 3
+	case http.MethodPatch:
+		return 4
+	case http.MethodDelete:
+		return 5
+	case http.MethodConnect:
+		return 6
+	case http.MethodOptions:
+		return 7
+	case http.MethodTrace:
+		return 8
+	default:
+		return -1
+	}
+}
+
+// compileTree compiles the tree below root, allocating all its nodes at once so they
+// are laid out next to each other.
+func compileTree(root *node) *cnode {
+	count := 0
+	root.walk(func(*node) {
+		count++
+	})
+
+	nodes := make([]cnode, 0, count)
+
+	var compile func(n *node) *cnode
+	compile = func(n *node) *cnode {
+		nodes = append(nodes, cnode{path: n.path, constraint: n.constraint})
+		c := &nodes[len(nodes)-1]
+
+		if len(n.leafHandler) != 0 {
+			c.leaf = n
+			for method, handler := range n.leafHandler {
+				if i := methodIndex(method); i >= 0 {
+					c.handlers[i] = handler
+				}
+			}
+		}
+
+		if len(n.staticIndices) != 0 {
+			lo, hi := n.staticIndices[0], n.staticIndices[0]
+			for _, index := range n.staticIndices {
+				if index < lo {
+					lo = index
+				}
+				if index > hi {
+					hi = index
+				}
+			}
+
+			c.indexBase = lo
+			c.indices = make([]uint16, int(hi-lo)+1)
+			c.staticChild = make([]*cnode, len(n.staticChild))
+			for i, child := range n.staticChild {
+				c.indices[n.staticIndices[i]-lo] = uint16(i + 1)
+				c.staticChild[i] = compile(child)
+			}
+		}
+
+		if len(n.wildcardChildren) != 0 {
+			c.wildcardChildren = make([]*cnode, len(n.wildcardChildren))
+			for i, child := range n.wildcardChildren {
+				c.wildcardChildren[i] = compile(child)
+			}
+		}
+
+		if n.catchAllChild != nil {
+			c.catchAllChild = compile(n.catchAllChild)
+		}
+
+		return c
+	}
+
+	return compile(root)
+}
+
+func (c *cnode) handler(method string) HandlerFunc {
+	if i := methodIndex(method); i >= 0 {
+		return c.handlers[i]
+	}
+
+	return c.leaf.leafHandler[method]
+}
+
+// search looks up the node matching path like node.search.
+func (c *cnode) search(method, path string, params *Params, rawPath bool) (found *node, handler HandlerFunc) {
+	pathLen := len(path)
+	if pathLen == 0 {
+		if c.leaf == nil {
+			return nil, nil
+		}
+
+		return c.leaf, c.handler(method)
+	}
+
+	base := len(*params)
+
+	if i := int(path[0]) - int(c.indexBase); i >= 0 && i < len(c.indices) {
+		if k := c.indices[i]; k != 0 {
+			child := c.staticChild[k-1]
+			childPathLen := len(child.path)
+			if pathLen >= childPathLen && child.path == path[:childPathLen] {
+				found, handler = child.search(method, path[childPathLen:], params, rawPath)
+			}
+		}
+	}
+
+	if handler != nil {
+		return
+	}
+
+	foundEnd := len(*params)
+
+	if len(c.wildcardChildren) != 0 {
+		nextSlash := strings.IndexByte(path, '/')
+		if nextSlash < 0 {
+			nextSlash = pathLen
+		}
+
+		thisToken := path[0:nextSlash]
+		nextToken := path[nextSlash:]
+
+		if len(thisToken) > 0 {
+			for _, wildcardChild := range c.wildcardChildren {
+				if wildcardChild.constraint != nil && !wildcardChild.constraint.matchToken(thisToken, rawPath) {
+					continue
+				}
+
+				wcNode, wcHandler := wildcardChild.search(method, nextToken, params, rawPath)
+				if wcHandler != nil || (found == nil && wcNode != nil) {
+					*params = append(*params, Parameter{Value: thisToken})
+
+					if wcHandler != nil {
+						*params = (*params)[:base+copy((*params)[base:], (*params)[foundEnd:])]
+						return wcNode, wcHandler
+					}
+
+					found = wcNode
+					handler = wcHandler
+					foundEnd = len(*params)
+				} else {
+					*params = (*params)[:foundEnd]
+				}
+			}
+		}
+	}
+
+	if catchAllChild := c.catchAllChild; catchAllChild != nil && catchAllChild.leaf != nil {
+		catchAllHandler := catchAllChild.handler(method)
+		if catchAllHandler != nil || found == nil {
+			*params = append((*params)[:base], Parameter{Value: path})
+			return catchAllChild.leaf, catchAllHandler
+		}
+	}
+
+	return found, handler
+}
@@ -0,0 +1,77 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestCompile(t *testing.T) {
+	router := httprouter.New()
+	router.GET("/users", httprouter.NoopHandler)
+	router.GET("/users/:id<int>", httprouter.NoopHandler)
+	router.GET("/users/:name", httprouter.NoopHandler)
+	router.POST("/users/:name/avatar", httprouter.NoopHandler)
+	router.Handler("PURGE", "/users/:name/avatar", httprouter.NoopHandler)
+	router.GET("/files/*path", httprouter.NoopHandler)
+	router.GET("/files/readme", httprouter.NoopHandler)
+	router.GET("/docs/", httprouter.NoopHandler)
+	router.Host(":tenant.example.com").GET("/users/:id", httprouter.NoopHandler)
+
+	compiled := router.Compile()
+	router.GET("/late", httprouter.NoopHandler)
+
+	tests := []struct {
+		method string
+		host   string
+		path   string
+	}{
+		{method: http.MethodGet, path: "/users"},
+		{method: http.MethodGet, path: "/users/42"},
+		{method: http.MethodGet, path: "/users/fry"},
+		{method: http.MethodPost, path: "/users/fry/avatar"},
+		{method: "PURGE", path: "/users/fry/avatar"},
+		{method: http.MethodGet, path: "/users/fry/avatar"},
+		{method: http.MethodGet, path: "/files/a/b"},
+		{method: http.MethodGet, path: "/files/readme"},
+		{method: http.MethodGet, path: "/docs"},
+		{method: http.MethodGet, path: "/missing"},
+		{method: http.MethodGet, host: "acme.example.com", path: "/users/1"},
+		{method: http.MethodGet, host: "acme.example.com", path: "/files/x"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.method+" "+test.host+test.path, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, nil)
+			if test.host != "" {
+				req.Host = test.host
+			}
+
+			expected := router.Lookup(nil, req)
+			defer expected.Release()
+
+			lr := compiled.Lookup(nil, req)
+			defer lr.Release()
+
+			if lr.Status != expected.Status {
+				t.Errorf("expected status %d, got %d", expected.Status, lr.Status)
+			}
+
+			if diff := cmp.Diff(expected.RouteData, lr.RouteData); diff != "" {
+				t.Error("unexpected route data", diff)
+			}
+		})
+	}
+
+	lr := compiled.Lookup(nil, httptest.NewRequest(http.MethodGet, "/late", nil))
+	defer lr.Release()
+
+	if lr.Status != http.StatusNotFound {
+		t.Errorf("expected route registered after compiling not to be found, got status %d", lr.Status)
+	}
+}
@@ -24,6 +24,31 @@ func WithRedirectTrailingSlash(redirectTrailingSlash bool) Opt {
 	}
 }
 
+// WithRedirectCleanPath redirects requests with duplicate slashes or dot segments in the path
+// to the cleaned path, if it matches a route.
+func WithRedirectCleanPath(redirectCleanPath bool) Opt {
+	return func(c *config) {
+		c.redirectCleanPath = redirectCleanPath
+	}
+}
+
+// WithRedirectCaseInsensitive redirects requests not matching any route to the path of a route
+// matching when the case of static segments is ignored.
+func WithRedirectCaseInsensitive(redirectCaseInsensitive bool) Opt {
+	return func(c *config) {
+		c.redirectCaseInsensitive = redirectCaseInsensitive
+	}
+}
+
+// WithRawPath routes requests on the escaped path of their URL, so encoded slashes don't
+// separate segments, and unescapes parameter values once matched. Static segments of
+// routes are matched in their escaped form.
+func WithRawPath(rawPath bool) Opt {
+	return func(c *config) {
+		c.rawPath = rawPath
+	}
+}
+
 func WithLogRoundtrip(logRoundtrip LogRoundtrip) Opt {
 	return func(c *config) {
 		c.logRoundtrip = logRoundtrip
@@ -58,6 +83,39 @@ func WithOptionsHandler(handler HandlerFunc) Opt {
 	}
 }
 
+// WithImplicitHead makes routes registered for GET also serve HEAD with the response body
+// discarded, unless HEAD is registered explicitly for the same path.
+func WithImplicitHead(implicitHead bool) Opt {
+	return func(c *config) {
+		c.implicitHead = implicitHead
+	}
+}
+
+// WithDeferredErrors makes Handler and Mount collect registration errors, to be returned
+// from Router.Build, instead of panicking.
+func WithDeferredErrors(deferErrors bool) Opt {
+	return func(c *config) {
+		c.deferErrors = deferErrors
+	}
+}
+
+// WithNotFoundHandler sets the handler for requests not matching any route. It's wrapped in
+// the global middleware and responsible for writing the response status.
+func WithNotFoundHandler(handler HandlerFunc) Opt {
+	return func(c *config) {
+		c.notFoundHandler = handler
+	}
+}
+
+// WithMethodNotAllowedHandler sets the handler for requests matching routes only for other
+// methods. It's wrapped in the global middleware and responsible for writing the response
+// status, while the Allow header is set before it's called.
+func WithMethodNotAllowedHandler(handler HandlerFunc) Opt {
+	return func(c *config) {
+		c.methodNotAllowedHandler = handler
+	}
+}
+
 func WithMiddleware(middleware ...Middleware) Opt {
 	return func(c *config) {
 		c.middleware = append(c.middleware, middleware...)
@@ -65,14 +123,21 @@ func WithMiddleware(middleware ...Middleware) Opt {
 }
 
 type config struct {
-	verbose               bool
-	handleOptions         bool
-	redirectTrailingSlash bool
-	logRoundtrip          LogRoundtrip
-	errorHandler          ErrorHandler
-	panicHandler          PanicHandler
-	optionsHandler        HandlerFunc
-	middleware            []Middleware
+	verbose                 bool
+	handleOptions           bool
+	redirectTrailingSlash   bool
+	redirectCleanPath       bool
+	redirectCaseInsensitive bool
+	rawPath                 bool
+	implicitHead            bool
+	deferErrors             bool
+	logRoundtrip            LogRoundtrip
+	errorHandler            ErrorHandler
+	panicHandler            PanicHandler
+	optionsHandler          HandlerFunc
+	notFoundHandler         HandlerFunc
+	methodNotAllowedHandler HandlerFunc
+	middleware              []Middleware
 }
 
 var defaultConfig = config{
@@ -0,0 +1,168 @@
+package httprouter
+
+import (
+	"net/url"
+	"regexp"
+	"sync"
+)
+
+// constraint restricts the values matched by a route parameter, written as
+// :name<expr> in a route pattern, where expr is either the name of a builtin
+// constraint or a regular expression that must match the whole segment.
+type constraint struct {
+	expr  string
+	match func(value string) bool
+}
+
+var builtinConstraints = map[string]func(value string) bool{
+	"int":   isInt,
+	"uint":  isUint,
+	"uuid":  isUUID,
+	"alpha": isAlpha,
+	"alnum": isAlnum,
+}
+
+// Constraints are shared between trees and URL generation, as regular expressions
+// are comparatively expensive to compile.
+var constraints sync.Map
+
+func newConstraint(expr string) (*constraint, error) {
+	if c, ok := constraints.Load(expr); ok {
+		return c.(*constraint), nil
+	}
+
+	match, ok := builtinConstraints[expr]
+	if !ok {
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, err
+		}
+		match = re.MatchString
+	}
+
+	c, _ := constraints.LoadOrStore(expr, &constraint{expr: expr, match: match})
+
+	return c.(*constraint), nil
+}
+
+// matchToken reports whether the path token satisfies c, unescaping it first if it's
+// taken from the escaped path.
+func (c *constraint) matchToken(token string, rawPath bool) bool {
+	if rawPath {
+		if unescaped, err := url.PathUnescape(token); err == nil {
+			token = unescaped
+		}
+	}
+
+	return c.match(token)
+}
+
+// splitParam splits a parameter token starting with ':' into its name and constraint
+// expression, returning the length of the token. Constraints may contain slashes, so
+// the token is only known to end at the '>' closing the constraint.
+func splitParam(path string) (name, expr string, end int, ok bool) {
+	end = len(path)
+	for i := 1; i < len(path); i++ {
+		switch path[i] {
+		case '/':
+			return path[1:i], "", i, true
+		case '<':
+			depth := 0
+			for j := i; j < len(path); j++ {
+				switch path[j] {
+				case '<':
+					depth++
+				case '>':
+					depth--
+				}
+
+				if depth == 0 {
+					return path[1:i], path[i+1 : j], j + 1, j+1 == len(path) || path[j+1] == '/'
+				}
+			}
+
+			return path[1:i], "", end, false
+		}
+	}
+
+	return path[1:], "", end, true
+}
+
+func isInt(value string) bool {
+	if len(value) > 1 && value[0] == '-' {
+		value = value[1:]
+	}
+
+	return isUint(value)
+}
+
+func isUint(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isUUID(value string) bool {
+	if len(value) != 36 {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if value[i] != '-' {
+				return false
+			}
+		default:
+			if !isHex(value[i]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func isAlpha(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		if !isLetter(value[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isAlnum(value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for i := 0; i < len(value); i++ {
+		if !isLetter(value[i]) && (value[i] < '0' || value[i] > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isHex(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
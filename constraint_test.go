@@ -0,0 +1,117 @@
+package httprouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestRouterParamConstraints(t *testing.T) {
+	handler := func(w http.ResponseWriter, req *http.Request) error {
+		data := httprouter.GetRouteData(req.Context())
+		_, err := fmt.Fprintf(w, "%s %v", data.Route, data.Params.Map())
+		return err
+	}
+
+	router := httprouter.New()
+	router.Handler(http.MethodGet, "/users/:id<int>", handler)
+	router.Handler(http.MethodGet, "/users/:name", handler)
+	router.Handler(http.MethodGet, "/users/me", handler)
+	router.Handler(http.MethodGet, "/files/:name<[a-z0-9-]+>/meta", handler)
+	router.Handler(http.MethodGet, "/v/:uuid<uuid>", handler)
+	router.Handler(http.MethodGet, "/orders/:id<uint>", handler, httprouter.Name("order"))
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "typed constraint",
+			path:           "/users/42",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "/users/:id<int> map[id:42]",
+		},
+		{
+			name:           "fall through to unconstrained parameter",
+			path:           "/users/fry",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "/users/:name map[name:fry]",
+		},
+		{
+			name:           "static route takes precedence",
+			path:           "/users/me",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "/users/me map[]",
+		},
+		{
+			name:           "regular expression constraint",
+			path:           "/files/report-2021/meta",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "/files/:name<[a-z0-9-]+>/meta map[name:report-2021]",
+		},
+		{
+			name:           "regular expression mismatch",
+			path:           "/files/Report/meta",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "uuid constraint",
+			path:           "/v/123e4567-e89b-12d3-a456-426614174000",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "/v/:uuid<uuid> map[uuid:123e4567-e89b-12d3-a456-426614174000]",
+		},
+		{
+			name:           "uuid mismatch",
+			path:           "/v/123e4567",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "uint mismatch",
+			path:           "/orders/-1",
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			if rec.Code != test.expectedStatus {
+				t.Fatalf("expected status code %d, got %d", test.expectedStatus, rec.Code)
+			}
+
+			if test.expectedBody == "" {
+				return
+			}
+
+			if diff := cmp.Diff(test.expectedBody, rec.Body.String()); diff != "" {
+				t.Error("unexpected body", diff)
+			}
+		})
+	}
+
+	if url, err := router.URL("order", map[string]string{"id": "7"}); err != nil || url != "/orders/7" {
+		t.Errorf("expected url /orders/7, got %q, %v", url, err)
+	}
+
+	if _, err := router.URL("order", map[string]string{"id": "seven"}); err == nil {
+		t.Error("expected constraint error")
+	}
+
+	if _, err := router.TryHandler(http.MethodGet, "/bad/:id<[a-z>", handler); err == nil {
+		t.Error("expected invalid constraint error")
+	}
+
+	if _, err := router.TryHandler(http.MethodGet, "/bad/:id<int>x", handler); err == nil {
+		t.Error("expected misplaced constraint error")
+	}
+}
@@ -1,10 +1,110 @@
 package httprouter
 
-import "context"
+import (
+	"context"
+	"sync"
+)
 
 type RouteData struct {
-	Route  string
-	Params map[string]string
+	Route string
+	// Mount is the prefix passed to Router.Mount when the route was registered by it.
+	Mount string
+	// Params are only valid until the handler returns, as their storage is reused
+	// for subsequent requests. Use Params.Copy or Params.Map to retain them.
+	Params Params
+	// Meta holds the metadata attached to the matched route with the Meta option.
+	// It's shared by all requests to the route and must not be modified.
+	Meta Metadata
+}
+
+// Metadata holds values attached to a route, keyed like context values.
+type Metadata map[interface{}]interface{}
+
+// Get returns the value of key, or nil if there is none.
+func (m Metadata) Get(key interface{}) interface{} {
+	return m[key]
+}
+
+func (m Metadata) clone() Metadata {
+	if m == nil {
+		return nil
+	}
+
+	c := make(Metadata, len(m))
+	for key, value := range m {
+		c[key] = value
+	}
+
+	return c
+}
+
+// Parameter is a single route parameter.
+type Parameter struct {
+	Key   string
+	Value string
+}
+
+// Params holds route parameters ordered as they appear in the host and route.
+type Params []Parameter
+
+// Get returns the value of parameter name, or an empty string if there is none.
+func (ps Params) Get(name string) string {
+	value, _ := ps.Lookup(name)
+
+	return value
+}
+
+// Lookup returns the value of parameter name and reports whether it's present.
+func (ps Params) Lookup(name string) (string, bool) {
+	for _, p := range ps {
+		if p.Key == name {
+			return p.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// Copy returns a copy of ps which can be retained after the handler returns.
+func (ps Params) Copy() Params {
+	if ps == nil {
+		return nil
+	}
+
+	dst := make(Params, len(ps))
+	copy(dst, ps)
+
+	return dst
+}
+
+// Map returns the parameters as a map keyed by name.
+func (ps Params) Map() map[string]string {
+	m := make(map[string]string, len(ps))
+	for _, p := range ps {
+		m[p.Key] = p.Value
+	}
+
+	return m
+}
+
+var paramsPool = sync.Pool{
+	New: func() interface{} {
+		ps := make(Params, 0, 8)
+		return &ps
+	},
+}
+
+func getParams() *Params {
+	return paramsPool.Get().(*Params)
+}
+
+func putParams(ps *Params) {
+	if ps == nil {
+		return
+	}
+
+	*ps = (*ps)[:0]
+	paramsPool.Put(ps)
 }
 
 type routeDataKey struct{}
@@ -14,12 +114,7 @@ func WithRouteData(ctx context.Context, data RouteData) context.Context {
 }
 
 func GetRouteData(ctx context.Context) RouteData {
-	data, ok := ctx.Value(routeDataKey{}).(RouteData)
-	if !ok {
-		return RouteData{
-			Params: make(map[string]string),
-		}
-	}
+	data, _ := ctx.Value(routeDataKey{}).(RouteData)
 	return data
 }
 
@@ -29,8 +124,18 @@ func GetRoute(ctx context.Context) string {
 	return data.Route
 }
 
+// MetaValue returns the metadata value of key attached to the matched route, and reports
+// whether it's present with type T.
+func MetaValue[T any](ctx context.Context, key interface{}) (T, bool) {
+	value, ok := GetRouteData(ctx).Meta[key].(T)
+
+	return value, ok
+}
+
+// GetParams returns the route parameters as a map, which unlike RouteData.Params
+// can be retained after the handler returns.
 func GetParams(ctx context.Context) map[string]string {
 	data := GetRouteData(ctx)
 
-	return data.Params
+	return data.Params.Map()
 }
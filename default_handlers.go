@@ -6,13 +6,16 @@ import (
 )
 
 type ErrorResponse struct {
-	Message string `json:"message"`
-	Debug   string `json:"debug,omitempty"`
+	Code    string           `json:"code,omitempty"`
+	Message string           `json:"message"`
+	Fields  []FieldViolation `json:"fields,omitempty"`
+	Debug   string           `json:"debug,omitempty"`
 }
 
 func DefaultErrorHandler(w http.ResponseWriter, req *http.Request, verbose bool, err Error) {
-	// do not write JSON response on http methods that do not return body
-	if req.Method == http.MethodHead || req.Method == http.MethodPut || req.Method == http.MethodTrace {
+	err.ApplyHeader(w)
+
+	if !writesErrorBody(req) {
 		w.WriteHeader(err.Status)
 		return
 	}
@@ -26,13 +29,21 @@ func DefaultErrorHandler(w http.ResponseWriter, req *http.Request, verbose bool,
 	}
 
 	resp := ErrorResponse{
+		Code:    err.Code,
 		Message: err.Message,
+		Fields:  err.Fields(),
 		Debug:   debug,
 	}
 
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
+// writesErrorBody reports whether error handlers write a body in response to req.
+func writesErrorBody(req *http.Request) bool {
+	// do not write JSON response on http methods that do not return body
+	return req.Method != http.MethodHead && req.Method != http.MethodPut && req.Method != http.MethodTrace
+}
+
 func DefaultPanicHandler(w http.ResponseWriter, req *http.Request, verbose bool, pv interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusInternalServerError)
@@ -4,13 +4,44 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 )
 
+// Error is an error with an HTTP status. It's comparable with ==, so the parts that aren't
+// are held by Extras.
 type Error struct {
 	Status      int
 	Message     string
 	Operational bool
 	Cause       error
+	// Code is a stable machine readable identifier of the error, e.g. USER_NOT_FOUND.
+	Code string
+	// Type is a URI reference identifying the problem type for ProblemErrorHandler.
+	Type string
+	// Detail explains this occurrence of the problem for ProblemErrorHandler.
+	Detail string
+	// Extras holds the field violations, headers and problem extensions, or nil if there are none.
+	// It's shared by copies of the Error and must not be modified once the Error is returned.
+	Extras *ErrorExtras
+}
+
+// ErrorExtras holds the parts of an Error that aren't comparable.
+type ErrorExtras struct {
+	// Fields lists the invalid fields of the request that caused the error.
+	Fields []FieldViolation
+	// Header holds response headers written along with the error, e.g. Retry-After.
+	Header http.Header
+	// Extensions are additional members of the problem details for ProblemErrorHandler.
+	Extensions map[string]interface{}
+}
+
+// FieldViolation describes an invalid field of a request.
+type FieldViolation struct {
+	// Field is a JSON pointer into the request body, or the name of a parameter.
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
 }
 
 func NewError(status int, opts ...ErrorOpt) Error {
@@ -27,6 +58,36 @@ func NewError(status int, opts ...ErrorOpt) Error {
 	return err
 }
 
+// Fields returns the invalid fields of the request that caused the error.
+func (e Error) Fields() []FieldViolation {
+	if e.Extras == nil {
+		return nil
+	}
+
+	return e.Extras.Fields
+}
+
+// Extensions returns the additional members of the problem details for ProblemErrorHandler.
+func (e Error) Extensions() map[string]interface{} {
+	if e.Extras == nil {
+		return nil
+	}
+
+	return e.Extras.Extensions
+}
+
+// ApplyHeader sets the headers of e on w, replacing existing values.
+// Error handlers call it before writing the status.
+func (e Error) ApplyHeader(w http.ResponseWriter) {
+	if e.Extras == nil {
+		return
+	}
+
+	for key, values := range e.Extras.Header {
+		w.Header()[key] = append([]string(nil), values...)
+	}
+}
+
 func (e Error) Unwrap() error {
 	return e.Cause
 }
@@ -35,8 +96,18 @@ func (e Error) Error() string {
 	return e.Message
 }
 
+// ErrorOpt configures an Error created by NewError.
 type ErrorOpt func(*Error)
 
+// mutableExtras returns the Extras of e, allocating them for the Error being created by NewError.
+func (e *Error) mutableExtras() *ErrorExtras {
+	if e.Extras == nil {
+		e.Extras = &ErrorExtras{}
+	}
+
+	return e.Extras
+}
+
 func Message(message string) ErrorOpt {
 	return func(e *Error) {
 		e.Message = message
@@ -61,21 +132,135 @@ func Cause(cause error) ErrorOpt {
 	}
 }
 
+// Code sets the machine readable identifier of the error.
+func Code(code string) ErrorOpt {
+	return func(e *Error) {
+		e.Code = code
+	}
+}
+
+// Field adds a violation of rule by field, described by message.
+func Field(field, rule, message string) ErrorOpt {
+	return func(e *Error) {
+		extras := e.mutableExtras()
+		extras.Fields = append(extras.Fields, FieldViolation{Field: field, Rule: rule, Message: message})
+	}
+}
+
+// Header adds value to the response header key.
+func Header(key, value string) ErrorOpt {
+	return func(e *Error) {
+		extras := e.mutableExtras()
+		if extras.Header == nil {
+			extras.Header = make(http.Header)
+		}
+
+		extras.Header.Add(key, value)
+	}
+}
+
+// RetryAfter sets the Retry-After response header to d, rounded up to whole seconds.
+func RetryAfter(d time.Duration) ErrorOpt {
+	seconds := int64((d + time.Second - 1) / time.Second)
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	return func(e *Error) {
+		extras := e.mutableExtras()
+		if extras.Header == nil {
+			extras.Header = make(http.Header)
+		}
+
+		extras.Header.Set("Retry-After", strconv.FormatInt(seconds, 10))
+	}
+}
+
+// Type sets the URI reference identifying the problem type.
+func Type(uri string) ErrorOpt {
+	return func(e *Error) {
+		e.Type = uri
+	}
+}
+
+// Detail sets the explanation of this occurrence of the problem.
+func Detail(detail string) ErrorOpt {
+	return func(e *Error) {
+		e.Detail = detail
+	}
+}
+
+// Extension adds a member to the problem details, which can't override the standard members.
+func Extension(key string, value interface{}) ErrorOpt {
+	return func(e *Error) {
+		extras := e.mutableExtras()
+		if extras.Extensions == nil {
+			extras.Extensions = make(map[string]interface{})
+		}
+
+		extras.Extensions[key] = value
+	}
+}
+
+// IsStatus reports whether StatusOf(err) is status.
 func IsStatus(err error, status int) bool {
-	var httpErr Error
-	switch {
-	case errors.Is(err, &httpErr):
-		return status == httpErr.Status
+	return StatusOf(err) == status
+}
+
+// StatusOf returns the status of the first Error in the chain of err, 500 if there is none,
+// or 200 if err is nil.
+func StatusOf(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+
+	return AsError(err).Status
+}
+
+// IsClientError reports whether StatusOf(err) is a 4xx status.
+func IsClientError(err error) bool {
+	status := StatusOf(err)
+	return status >= 400 && status < 500
+}
+
+// IsServerError reports whether StatusOf(err) is a 5xx status.
+func IsServerError(err error) bool {
+	return StatusOf(err) >= 500
+}
+
+// Is reports whether target is an Error, or a pointer to one, with the same status and code
+// as e. A zero status or empty code of target matches any, but not both.
+func (e Error) Is(target error) bool {
+	var t Error
+	switch target := target.(type) {
+	case Error:
+		t = target
+	case *Error:
+		if target == nil {
+			return false
+		}
+		t = *target
 	default:
-		return status == http.StatusInternalServerError
+		return false
+	}
+
+	if t.Status == 0 && t.Code == "" {
+		return false
 	}
+
+	return (t.Status == 0 || t.Status == e.Status) && (t.Code == "" || t.Code == e.Code)
 }
 
 func AsError(err error) Error {
 	httpErr := Error{}
-	if !errors.As(err, &httpErr) {
-		return NewError(http.StatusInternalServerError, Cause(err))
+	if errors.As(err, &httpErr) {
+		return httpErr
+	}
+
+	var httpErrPtr *Error
+	if errors.As(err, &httpErrPtr) && httpErrPtr != nil {
+		return *httpErrPtr
 	}
 
-	return httpErr
+	return NewError(http.StatusInternalServerError, Cause(err))
 }
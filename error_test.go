@@ -0,0 +1,159 @@
+package httprouter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestErrorHeader(t *testing.T) {
+	handler := func(w http.ResponseWriter, req *http.Request) error {
+		return httprouter.NewError(
+			http.StatusTooManyRequests,
+			httprouter.RetryAfter(1500*time.Millisecond),
+			httprouter.Header("WWW-Authenticate", `Bearer realm="api"`),
+			httprouter.Header("WWW-Authenticate", `Basic realm="api"`),
+		)
+	}
+
+	errorHandlers := map[string]httprouter.ErrorHandler{
+		"default": httprouter.DefaultErrorHandler,
+		"problem": httprouter.ProblemErrorHandler,
+	}
+
+	for name, errorHandler := range errorHandlers {
+		errorHandler := errorHandler
+		for _, method := range []string{http.MethodGet, http.MethodHead} {
+			method := method
+			t.Run(name+" "+method, func(t *testing.T) {
+				router := httprouter.New(httprouter.WithErrorHandler(errorHandler))
+				router.Handler(method, "/limited", handler)
+
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, httptest.NewRequest(method, "/limited", nil))
+
+				if rec.Code != http.StatusTooManyRequests {
+					t.Fatalf("expected status code %d, got %d", http.StatusTooManyRequests, rec.Code)
+				}
+
+				expected := http.Header{
+					"Retry-After":      {"2"},
+					"Www-Authenticate": {`Bearer realm="api"`, `Basic realm="api"`},
+				}
+				actual := rec.Result().Header
+				actual.Del("Content-Type")
+
+				if diff := cmp.Diff(expected, actual); diff != "" {
+					t.Error("unexpected headers", diff)
+				}
+			})
+		}
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	notFound := httprouter.NewError(http.StatusNotFound, httprouter.Code("USER_NOT_FOUND"))
+	unavailable := httprouter.NewError(http.StatusServiceUnavailable)
+
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		clientError    bool
+		serverError    bool
+	}{
+		{name: "nil", err: nil, expectedStatus: http.StatusOK},
+		{name: "error", err: notFound, expectedStatus: http.StatusNotFound, clientError: true},
+		{name: "pointer", err: &notFound, expectedStatus: http.StatusNotFound, clientError: true},
+		{name: "wrapped", err: fmt.Errorf("load user: %w", notFound), expectedStatus: http.StatusNotFound, clientError: true},
+		{name: "wrapped pointer", err: fmt.Errorf("load user: %w", &notFound), expectedStatus: http.StatusNotFound, clientError: true},
+		{name: "wrapped twice", err: fmt.Errorf("handler: %w", fmt.Errorf("upstream: %w", unavailable)), expectedStatus: http.StatusServiceUnavailable, serverError: true},
+		{name: "plain", err: errors.New("boom"), expectedStatus: http.StatusInternalServerError, serverError: true},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if status := httprouter.StatusOf(test.err); status != test.expectedStatus {
+				t.Errorf("expected status %d, got %d", test.expectedStatus, status)
+			}
+
+			if !httprouter.IsStatus(test.err, test.expectedStatus) {
+				t.Errorf("expected IsStatus to report status %d", test.expectedStatus)
+			}
+
+			if httprouter.IsStatus(test.err, http.StatusTeapot) {
+				t.Error("expected IsStatus not to report another status")
+			}
+
+			if clientError := httprouter.IsClientError(test.err); clientError != test.clientError {
+				t.Errorf("expected IsClientError %t, got %t", test.clientError, clientError)
+			}
+
+			if serverError := httprouter.IsServerError(test.err); serverError != test.serverError {
+				t.Errorf("expected IsServerError %t, got %t", test.serverError, serverError)
+			}
+		})
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	err := fmt.Errorf("load user: %w", httprouter.NewError(
+		http.StatusNotFound,
+		httprouter.Code("USER_NOT_FOUND"),
+		httprouter.Field("/id", "exists", "User does not exist"),
+	))
+
+	tests := []struct {
+		name     string
+		target   error
+		expected bool
+	}{
+		{name: "status", target: httprouter.Error{Status: http.StatusNotFound}, expected: true},
+		{name: "code", target: httprouter.Error{Code: "USER_NOT_FOUND"}, expected: true},
+		{name: "status and code", target: httprouter.NewError(http.StatusNotFound, httprouter.Code("USER_NOT_FOUND")), expected: true},
+		{name: "pointer", target: &httprouter.Error{Status: http.StatusNotFound}, expected: true},
+		{name: "other status", target: httprouter.Error{Status: http.StatusGone}},
+		{name: "other code", target: httprouter.Error{Status: http.StatusNotFound, Code: "ORDER_NOT_FOUND"}},
+		{name: "zero", target: httprouter.Error{}},
+		{name: "other error", target: errors.New("not found")},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if is := errors.Is(err, test.target); is != test.expected {
+				t.Errorf("expected errors.Is %t, got %t", test.expected, is)
+			}
+		})
+	}
+}
+
+func TestErrorComparable(t *testing.T) {
+	conflict := httprouter.NewError(http.StatusConflict,
+		httprouter.Field("/email", "unique", "Email is taken"),
+		httprouter.Header("X-Reason", "duplicate"),
+		httprouter.Extension("email", "fry@example.com"),
+	)
+	copied := conflict
+
+	var err error = conflict
+	if err != copied {
+		t.Error("expected copies of an error to be equal")
+	}
+
+	if err == httprouter.NewError(http.StatusConflict) {
+		t.Error("expected errors with different extras to differ")
+	}
+
+	if !errors.Is(err, httprouter.NewError(http.StatusConflict)) {
+		t.Error("expected errors with the same status to match")
+	}
+}
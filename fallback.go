@@ -0,0 +1,132 @@
+package httprouter
+
+import "strings"
+
+// fallback holds the handlers serving requests below prefix that don't match any route,
+// or only match routes for other methods.
+type fallback struct {
+	host             *host
+	prefix           string
+	notFound         HandlerFunc
+	methodNotAllowed HandlerFunc
+}
+
+func (f *fallback) match(hostname, path string) bool {
+	if f.host != nil {
+		var scratch Params
+		if !f.host.match(hostname, &scratch) {
+			return false
+		}
+	}
+
+	return matchPrefix(f.prefix, path)
+}
+
+// matchPrefix reports whether path is the group prefix or below it, matching
+// parameters of prefix with any non empty segment.
+func matchPrefix(prefix, path string) bool {
+	for len(prefix) > 0 {
+		if len(path) == 0 || path[0] != '/' {
+			return false
+		}
+		prefix, path = prefix[1:], path[1:]
+
+		end := strings.IndexByte(path, '/')
+		if end < 0 {
+			end = len(path)
+		}
+
+		if strings.HasPrefix(prefix, ":") {
+			_, _, paramEnd, _ := splitParam(prefix)
+			if end == 0 {
+				return false
+			}
+
+			prefix, path = prefix[paramEnd:], path[end:]
+			continue
+		}
+
+		segmentEnd := strings.IndexByte(prefix, '/')
+		if segmentEnd < 0 {
+			segmentEnd = len(prefix)
+		}
+
+		if path[:end] != strings.TrimPrefix(prefix[:segmentEnd], `\`) {
+			return false
+		}
+
+		prefix, path = prefix[segmentEnd:], path[end:]
+	}
+
+	return len(path) == 0 || path[0] == '/'
+}
+
+func (r *Router) addFallback(h *host, prefix string, notFound, methodNotAllowed HandlerFunc) {
+	_ = r.update(func(t *table) error {
+		for i, f := range t.fallbacks {
+			if f.host == h && f.prefix == prefix {
+				updated := *f
+				if notFound != nil {
+					updated.notFound = notFound
+				}
+				if methodNotAllowed != nil {
+					updated.methodNotAllowed = methodNotAllowed
+				}
+				t.fallbacks[i] = &updated
+
+				return nil
+			}
+		}
+
+		f := &fallback{
+			host:             h,
+			prefix:           prefix,
+			notFound:         notFound,
+			methodNotAllowed: methodNotAllowed,
+		}
+
+		// Keep fallbacks ordered by specificity, so the first match is the most specific one.
+		i := 0
+		for i < len(t.fallbacks) && moreSpecific(t.fallbacks[i], f) {
+			i++
+		}
+
+		t.fallbacks = append(t.fallbacks, nil)
+		copy(t.fallbacks[i+1:], t.fallbacks[i:])
+		t.fallbacks[i] = f
+
+		return nil
+	})
+}
+
+func moreSpecific(a, b *fallback) bool {
+	if len(a.prefix) != len(b.prefix) {
+		return len(a.prefix) > len(b.prefix)
+	}
+
+	return a.host != nil || b.host == nil
+}
+
+// notFound returns the handler for requests not matching any route, or nil if
+// the error handler should respond.
+func (t *table) notFound(hostname, path string) HandlerFunc {
+	for _, f := range t.fallbacks {
+		if f.notFound != nil && f.match(hostname, path) {
+			return f.notFound
+		}
+	}
+
+	return nil
+}
+
+// methodNotAllowed returns the handler for requests matching routes only for other
+// methods, or nil if the error handler should respond.
+func (t *table) methodNotAllowed(hostname, path string) HandlerFunc {
+	for _, f := range t.fallbacks {
+		if f.methodNotAllowed != nil && f.match(hostname, path) {
+			return f.methodNotAllowed
+		}
+	}
+
+	return nil
+}
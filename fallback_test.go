@@ -0,0 +1,182 @@
+package httprouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestRouterFallbackHandlers(t *testing.T) {
+	trace := func(name string) httprouter.Middleware {
+		return func(next httprouter.HandlerFunc) httprouter.HandlerFunc {
+			return func(w http.ResponseWriter, req *http.Request) error {
+				w.Header().Add("X-Trace", name)
+				return next(w, req)
+			}
+		}
+	}
+
+	respond := func(status int, body string) httprouter.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) error {
+			w.WriteHeader(status)
+			_, err := fmt.Fprint(w, body)
+			return err
+		}
+	}
+
+	router := httprouter.New(
+		httprouter.WithMiddleware(trace("global")),
+		httprouter.WithNotFoundHandler(respond(http.StatusNotFound, "global not found")),
+		httprouter.WithMethodNotAllowedHandler(respond(http.StatusMethodNotAllowed, "global method not allowed")),
+	)
+	router.Handler(http.MethodGet, "/users", httprouter.NoopHandler)
+
+	app := router.Group("/app", trace("app"))
+	app.NotFoundHandler(respond(http.StatusOK, "index.html"))
+	app.Handler(http.MethodGet, "/assets/site.css", httprouter.NoopHandler)
+
+	api := router.Group("/api", trace("api"))
+	api.MethodNotAllowedHandler(respond(http.StatusMethodNotAllowed, "api method not allowed"))
+	api.Handler(http.MethodGet, "/users", httprouter.NoopHandler)
+
+	tenants := router.Group("/tenants/:tenant")
+	tenants.NotFoundHandler(respond(http.StatusNotFound, "tenant not found"))
+	tenants.MethodNotAllowedHandler(respond(http.StatusMethodNotAllowed, "tenant method not allowed"))
+	tenants.Handler(http.MethodGet, "/users", httprouter.NoopHandler)
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		expectedStatus  int
+		expectedBody    string
+		expectedHeaders http.Header
+	}{
+		{
+			name:           "global not found",
+			method:         http.MethodGet,
+			path:           "/unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "global not found",
+			expectedHeaders: http.Header{
+				"X-Trace": {"global"},
+			},
+		},
+		{
+			name:           "global method not allowed",
+			method:         http.MethodPost,
+			path:           "/users",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "global method not allowed",
+			expectedHeaders: http.Header{
+				"X-Trace": {"global"},
+				"Allow":   {"GET, OPTIONS"},
+			},
+		},
+		{
+			name:           "group not found",
+			method:         http.MethodGet,
+			path:           "/app/settings/profile",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "index.html",
+			expectedHeaders: http.Header{
+				"X-Trace": {"global", "app"},
+			},
+		},
+		{
+			name:           "group falls back to global method not allowed",
+			method:         http.MethodPost,
+			path:           "/app/assets/site.css",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "global method not allowed",
+		},
+		{
+			name:           "group method not allowed",
+			method:         http.MethodDelete,
+			path:           "/api/users",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "api method not allowed",
+			expectedHeaders: http.Header{
+				"X-Trace": {"global", "api"},
+			},
+		},
+		{
+			name:           "group falls back to global not found",
+			method:         http.MethodGet,
+			path:           "/api/unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "global not found",
+		},
+		{
+			name:           "parameterised group not found",
+			method:         http.MethodGet,
+			path:           "/tenants/acme/unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "tenant not found",
+		},
+		{
+			name:           "parameterised group method not allowed",
+			method:         http.MethodPost,
+			path:           "/tenants/acme/users",
+			expectedStatus: http.StatusMethodNotAllowed,
+			expectedBody:   "tenant method not allowed",
+		},
+		{
+			name:           "parameter matches non empty segments",
+			method:         http.MethodGet,
+			path:           "/tenants//unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "global not found",
+		},
+		{
+			name:           "prefix matches whole segments",
+			method:         http.MethodGet,
+			path:           "/application",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   "global not found",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(test.method, test.path, nil))
+
+			if rec.Code != test.expectedStatus {
+				t.Fatalf("expected status code %d, got %d", test.expectedStatus, rec.Code)
+			}
+
+			if diff := cmp.Diff(test.expectedBody, rec.Body.String()); diff != "" {
+				t.Error("unexpected body", diff)
+			}
+
+			for k, v := range test.expectedHeaders {
+				if diff := cmp.Diff(v, rec.Header()[k]); diff != "" {
+					t.Errorf("unexpected header %q %s", k, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestRouterDefaultMethodNotAllowedAllowHeader(t *testing.T) {
+	router := httprouter.New()
+	router.Handler(http.MethodGet, "/users", httprouter.NoopHandler)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/users", nil))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("expected status code %d, got %d", http.StatusMethodNotAllowed, rec.Code)
+	}
+
+	if allow := rec.Header().Get("Allow"); allow == "" {
+		t.Error("expected Allow header")
+	}
+}
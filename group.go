@@ -0,0 +1,118 @@
+package httprouter
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Group registers routes under a shared path prefix and middleware chain.
+// Groups can be nested, in which case prefixes and middleware are composed
+// in declaration order.
+type Group struct {
+	router     *Router
+	host       *host
+	prefix     string
+	middleware []Middleware
+}
+
+// Group creates a nested group with prefix appended to the prefix of g and
+// middleware applied after the middleware of g.
+func (g *Group) Group(prefix string, middleware ...Middleware) *Group {
+	if err := validatePath(prefix); err != nil {
+		g.router.check(err)
+		// Registration goes on below the intended prefix, so the error is reported once.
+		prefix = "/" + prefix
+	}
+
+	return &Group{
+		router:     g.router,
+		host:       g.host,
+		prefix:     g.prefix + strings.TrimSuffix(prefix, "/"),
+		middleware: joinMiddleware(g.middleware, middleware),
+	}
+}
+
+// Handler registers HandlerFunc at given method and path relative to the group prefix
+func (g *Group) Handler(method, path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	route, err := g.TryHandler(method, path, handler, opts...)
+	g.router.check(err)
+
+	return route
+}
+
+// TryHandler registers HandlerFunc at given method and path relative to the group prefix,
+// returning an error instead of panicking like Router.TryHandler.
+func (g *Group) TryHandler(method, path string, handler HandlerFunc, opts ...RouteOpt) (*Route, error) {
+	opts = append([]RouteOpt{Use(g.middleware...)}, opts...)
+
+	if err := validatePath(path); err != nil {
+		return g.router.newRoutes(g.host, []string{method}, g.prefix+path, newRouteConfig(opts))[0], err
+	}
+
+	return g.router.handle(g.host, method, g.prefix+path, handler, opts)
+}
+
+// Handle registers HandlerFunc at given path relative to the group prefix for each of methods
+// like Router.Handle.
+func (g *Group) Handle(methods []string, path string, handler HandlerFunc, opts ...RouteOpt) []*Route {
+	opts = append([]RouteOpt{Use(g.middleware...)}, opts...)
+
+	if err := validatePath(path); err != nil {
+		g.router.check(err)
+		return g.router.newRoutes(g.host, methods, g.prefix+path, newRouteConfig(opts))
+	}
+
+	routes, err := g.router.handleMethods(g.host, methods, g.prefix+path, handler, opts)
+	g.router.check(err)
+
+	return routes
+}
+
+// Any registers HandlerFunc at given path relative to the group prefix for every standard method.
+func (g *Group) Any(path string, handler HandlerFunc, opts ...RouteOpt) []*Route {
+	return g.Handle(standardMethods, path, handler, opts...)
+}
+
+// HTTPHandler registers http.Handler at given method and path relative to the group prefix
+func (g *Group) HTTPHandler(method, path string, handler http.Handler, opts ...RouteOpt) *Route {
+	return g.Handler(method, path, wrapHTTPHandler(handler), opts...)
+}
+
+// Mount registers handler for every standard method at prefix relative to the group prefix
+// and any path below it. The full prefix is stripped from the request path before handler is called.
+func (g *Group) Mount(prefix string, handler http.Handler, middleware ...Middleware) {
+	if err := validatePath(prefix); err != nil {
+		g.router.check(err)
+		return
+	}
+
+	g.router.check(g.router.mount(g.host, g.prefix+prefix, handler, joinMiddleware(g.middleware, middleware)))
+}
+
+// Remove removes the route registered at given method and path relative to the group prefix,
+// and reports whether it existed.
+func (g *Group) Remove(method, path string) bool {
+	return g.router.remove(g.host, method, g.prefix+path)
+}
+
+// NotFoundHandler sets the handler for requests below the group prefix not matching any route,
+// taking precedence over handlers of enclosing groups and WithNotFoundHandler. It's wrapped
+// in the global and group middleware and responsible for writing the response status.
+func (g *Group) NotFoundHandler(handler HandlerFunc) {
+	g.router.addFallback(g.host, g.prefix, g.router.chain(handler, g.middleware), nil)
+}
+
+// MethodNotAllowedHandler sets the handler for requests below the group prefix matching
+// routes only for other methods, taking precedence over handlers of enclosing groups and
+// WithMethodNotAllowedHandler. It's wrapped in the global and group middleware and
+// responsible for writing the response status.
+func (g *Group) MethodNotAllowedHandler(handler HandlerFunc) {
+	g.router.addFallback(g.host, g.prefix, nil, g.router.chain(handler, g.middleware))
+}
+
+func joinMiddleware(outer, inner []Middleware) []Middleware {
+	joined := make([]Middleware, 0, len(outer)+len(inner))
+	joined = append(joined, outer...)
+
+	return append(joined, inner...)
+}
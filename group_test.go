@@ -0,0 +1,94 @@
+package httprouter_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestGroup(t *testing.T) {
+	trace := func(name string) httprouter.Middleware {
+		return func(next httprouter.HandlerFunc) httprouter.HandlerFunc {
+			return func(w http.ResponseWriter, req *http.Request) error {
+				w.Header().Add("X-Trace", name)
+				return next(w, req)
+			}
+		}
+	}
+
+	handler := func(w http.ResponseWriter, req *http.Request) error {
+		_, err := io.WriteString(w, httprouter.GetRoute(req.Context()))
+		return err
+	}
+
+	router := httprouter.New(httprouter.WithMiddleware(trace("global")))
+
+	api := router.Group("/api/v1", trace("api"))
+	api.Handler(http.MethodGet, "/users/:id", handler)
+
+	admin := api.Group("/admin/", trace("admin"))
+	admin.Handler(http.MethodGet, "/users", handler, httprouter.Use(trace("route")))
+	admin.HTTPHandler(http.MethodGet, "/health", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+
+	router.Handler(http.MethodGet, "/users", handler)
+
+	tests := []struct {
+		name          string
+		path          string
+		expectedBody  string
+		expectedTrace []string
+	}{
+		{
+			name:          "group route",
+			path:          "/api/v1/users/1",
+			expectedBody:  "/api/v1/users/:id",
+			expectedTrace: []string{"global", "api"},
+		},
+		{
+			name:          "nested group route",
+			path:          "/api/v1/admin/users",
+			expectedBody:  "/api/v1/admin/users",
+			expectedTrace: []string{"global", "api", "admin", "route"},
+		},
+		{
+			name:          "nested group http handler",
+			path:          "/api/v1/admin/health",
+			expectedBody:  "ok",
+			expectedTrace: []string{"global", "api", "admin"},
+		},
+		{
+			name:          "root route",
+			path:          "/users",
+			expectedBody:  "/users",
+			expectedTrace: []string{"global"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status code %d, got %d", http.StatusOK, rec.Code)
+			}
+
+			if body := strings.TrimSpace(rec.Body.String()); body != test.expectedBody {
+				t.Errorf("expected body %q, got %q", test.expectedBody, body)
+			}
+
+			if diff := cmp.Diff(test.expectedTrace, rec.Header()["X-Trace"]); diff != "" {
+				t.Error("unexpected middleware trace", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package httprouter
+
+import "strings"
+
+// host is a pattern for the hosts of requests served by its own route tree.
+type host struct {
+	pattern string
+	// The labels of pattern, where labels starting with ':' capture the matched label.
+	labels []string
+}
+
+// newHost creates the host for pattern. A host with an invalid pattern has no labels
+// and matches no hostname, so its routes are never served.
+func newHost(pattern string) *host {
+	h := &host{pattern: pattern}
+	if validateHost(pattern) == nil {
+		h.labels = strings.Split(strings.ToLower(pattern), ".")
+	}
+
+	return h
+}
+
+func validateHost(pattern string) error {
+	if pattern == "" {
+		return &InvalidPathError{Path: pattern, Reason: "host pattern must be non empty"}
+	}
+
+	for _, label := range strings.Split(pattern, ".") {
+		if label == "" || label == ":" {
+			return &InvalidPathError{Path: pattern, Reason: "host pattern has empty label"}
+		}
+	}
+
+	return nil
+}
+
+func (h *host) wildcard() bool {
+	for _, label := range h.labels {
+		if label[0] == ':' {
+			return true
+		}
+	}
+
+	return false
+}
+
+// match reports whether hostname matches the host pattern, appending the
+// captured labels to params.
+func (h *host) match(hostname string, params *Params) bool {
+	if len(h.labels) == 0 {
+		return false
+	}
+
+	base := len(*params)
+	for _, label := range h.labels {
+		next := strings.IndexByte(hostname, '.')
+		if next < 0 {
+			next = len(hostname)
+		}
+
+		value := hostname[:next]
+		switch {
+		case value == "":
+			*params = (*params)[:base]
+			return false
+		case label[0] == ':':
+			*params = append(*params, Parameter{Key: label[1:], Value: value})
+		case !strings.EqualFold(label, value):
+			*params = (*params)[:base]
+			return false
+		}
+
+		hostname = strings.TrimPrefix(hostname[next:], ".")
+	}
+
+	if hostname != "" {
+		*params = (*params)[:base]
+		return false
+	}
+
+	return true
+}
+
+// Host creates a Group of routes that are only matched for requests to hosts matching pattern.
+// Pattern labels starting with ':' match any label and capture it as a route parameter,
+// e.g. ":tenant.example.com". The port of the request host is ignored.
+// Static host patterns are matched before wildcard ones, and requests not matching any
+// route of a host, or only routes for other methods, fall back to routes registered without host.
+func (r *Router) Host(pattern string, middleware ...Middleware) *Group {
+	r.check(validateHost(pattern))
+
+	return &Group{
+		router:     r,
+		host:       r.host(pattern),
+		middleware: joinMiddleware(nil, middleware),
+	}
+}
+
+func (r *Router) host(pattern string) *host {
+	var h *host
+	_ = r.update(func(t *table) error {
+		for _, ht := range t.hosts {
+			if ht.host.pattern == pattern {
+				h = ht.host
+				return errUnchanged
+			}
+		}
+
+		h = newHost(pattern)
+		ht := hostTree{host: h, root: &node{path: "/"}}
+		if h.wildcard() {
+			t.hosts = append(t.hosts, ht)
+			return nil
+		}
+
+		// Keep static hosts before wildcard ones.
+		i := 0
+		for i < len(t.hosts) && !t.hosts[i].host.wildcard() {
+			i++
+		}
+
+		t.hosts = append(t.hosts, hostTree{})
+		copy(t.hosts[i+1:], t.hosts[i:])
+		t.hosts[i] = ht
+
+		return nil
+	})
+
+	return h
+}
+
+// hostname returns the host without port, not using net.SplitHostPort as it allocates
+// an error for hosts without port.
+func hostname(hostport string) string {
+	if i := strings.LastIndexByte(hostport, ':'); i >= 0 && strings.IndexByte(hostport[i:], ']') < 0 {
+		hostport = hostport[:i]
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(hostport, "["), "]")
+}
@@ -0,0 +1,123 @@
+package httprouter_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestRouterHost(t *testing.T) {
+	handler := func(name string) httprouter.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) error {
+			data := httprouter.GetRouteData(req.Context())
+			_, err := fmt.Fprintf(w, "%s %s %v", name, data.Route, data.Params)
+			return err
+		}
+	}
+
+	router := httprouter.New()
+	router.Handler(http.MethodGet, "/users/:id", handler("default"))
+	router.Handler(http.MethodGet, "/healthz", handler("default"))
+	router.Host(":tenant.example.com").Handler(http.MethodGet, "/users/:id", handler("tenant"))
+	router.Host(":tenant.example.com").Handler(http.MethodPost, "/healthz", handler("tenant"))
+	router.Host("api.example.com").Group("/v1").Handler(http.MethodGet, "/users/:id", handler("api"))
+	router.Host("admin.example.com").Handler(http.MethodPost, "/users/:id", handler("admin"))
+	router.Host("admin.example.com").Handler(http.MethodPost, "/reports", handler("admin"))
+
+	tests := []struct {
+		name           string
+		host           string
+		path           string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "static host",
+			host:           "api.example.com",
+			path:           "/v1/users/1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "api /v1/users/:id [{id 1}]",
+		},
+		{
+			name:           "static host with port",
+			host:           "API.example.com:8080",
+			path:           "/v1/users/1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "api /v1/users/:id [{id 1}]",
+		},
+		{
+			name:           "wildcard host",
+			host:           "acme.example.com",
+			path:           "/users/1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "tenant /users/:id [{tenant acme} {id 1}]",
+		},
+		{
+			name:           "fall back to default routes",
+			host:           "acme.example.com",
+			path:           "/healthz",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "default /healthz []",
+		},
+		{
+			name:           "unknown host",
+			host:           "example.org",
+			path:           "/users/1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "default /users/:id [{id 1}]",
+		},
+		{
+			name:           "wildcard host does not match nested subdomain",
+			host:           "a.b.example.com",
+			path:           "/users/1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "default /users/:id [{id 1}]",
+		},
+		{
+			name:           "fall back to default routes for other methods",
+			host:           "admin.example.com",
+			path:           "/users/1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "default /users/:id [{id 1}]",
+		},
+		{
+			name:           "method not allowed on host",
+			host:           "admin.example.com",
+			path:           "/reports",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			req.Host = test.host
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != test.expectedStatus {
+				t.Fatalf("expected status code %d, got %d", test.expectedStatus, rec.Code)
+			}
+
+			if test.expectedBody == "" {
+				return
+			}
+
+			if diff := cmp.Diff(test.expectedBody, rec.Body.String()); diff != "" {
+				t.Error("unexpected body", diff)
+			}
+		})
+	}
+
+	routes := router.Routes()
+	if len(routes) != 7 || routes[0].Host != "" || routes[2].Host != ":tenant.example.com" {
+		t.Errorf("unexpected routes %+v", routes)
+	}
+}
@@ -2,16 +2,19 @@ package httprouter
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"mime"
 	"net/http"
+	"strings"
 )
 
 // JSONRequest expects application/json content-type and attempts
 // to unmarshal request body into dst.
 // 415 Unsupported Media Type is returned if invalid content-type was provided
-// 400 Bad Request is returned if request body failed to unmarshal
+// 400 Bad Request is returned if request body failed to unmarshal, with the field
+// of a mismatched type listed in Error.Fields
 func JSONRequest(req *http.Request, dst interface{}) error {
 	mt, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
 	if err != nil || mt != "application/json" {
@@ -24,16 +27,41 @@ func JSONRequest(req *http.Request, dst interface{}) error {
 	}
 
 	if err := json.Unmarshal(data, dst); err != nil {
-		return NewError(
-			http.StatusBadRequest,
-			Message("Failed to unmarshal request body"),
-			Cause(err),
-		)
+		opts := []ErrorOpt{Message("Failed to unmarshal request body"), Cause(err)}
+
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Field != "" {
+			opts = append(opts, Field(
+				jsonPointer(typeErr.Field),
+				"type",
+				fmt.Sprintf("Must be of type %s", typeErr.Type),
+			))
+		}
+
+		return NewError(http.StatusBadRequest, opts...)
 	}
 
 	return nil
 }
 
+// jsonPointer converts the dotted path of a field reported by encoding/json
+// to a JSON pointer, escaping its segments as per RFC 6901 unless encoding/json did.
+func jsonPointer(field string) string {
+	var b strings.Builder
+	for _, segment := range strings.Split(field, ".") {
+		b.WriteByte('/')
+		if jsonFieldEscaped {
+			b.WriteString(segment)
+		} else {
+			b.WriteString(pointerEscaper.Replace(segment))
+		}
+	}
+
+	return b.String()
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
 // JSONResponse sets content-type to application/json and marshals src
 // as json to response body
 // 500 Internal Server Error is returned if src could not be marshaled
@@ -0,0 +1,7 @@
+//go:build !goexperiment.jsonv2
+
+package httprouter
+
+// jsonFieldEscaped reports whether encoding/json escapes the names in the path of
+// json.UnmarshalTypeError.Field. The original implementation joins them unescaped.
+const jsonFieldEscaped = false
@@ -0,0 +1,8 @@
+//go:build goexperiment.jsonv2
+
+package httprouter
+
+// jsonFieldEscaped reports whether encoding/json escapes the names in the path of
+// json.UnmarshalTypeError.Field. The implementation backed by encoding/json/v2 derives it
+// from a JSON pointer, so the names are already escaped.
+const jsonFieldEscaped = true
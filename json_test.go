@@ -0,0 +1,105 @@
+package httprouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestJSONRequestFieldViolations(t *testing.T) {
+	router := httprouter.New()
+	router.POST("/users", func(w http.ResponseWriter, req *http.Request) error {
+		var user struct {
+			Name    string `json:"name"`
+			Address struct {
+				Zip int `json:"zip"`
+			} `json:"address"`
+			Weight int `json:"weight~/kg"`
+		}
+
+		if err := httprouter.JSONRequest(req, &user); err != nil {
+			return err
+		}
+
+		if user.Name == "" {
+			return httprouter.NewError(
+				http.StatusUnprocessableEntity,
+				httprouter.Field("/name", "required", "Name is required"),
+				httprouter.Operational(),
+			)
+		}
+
+		return nil
+	})
+
+	tests := []struct {
+		name             string
+		body             string
+		expectedStatus   int
+		expectedResponse httprouter.ErrorResponse
+	}{
+		{
+			name:           "type mismatch",
+			body:           `{"name": "fry", "address": {"zip": "10001"}}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: httprouter.ErrorResponse{
+				Message: "Failed to unmarshal request body",
+				Fields: []httprouter.FieldViolation{
+					{Field: "/address/zip", Rule: "type", Message: "Must be of type int"},
+				},
+			},
+		},
+		{
+			name:           "escaped pointer",
+			body:           `{"name": "fry", "weight~/kg": "80"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: httprouter.ErrorResponse{
+				Message: "Failed to unmarshal request body",
+				Fields: []httprouter.FieldViolation{
+					{Field: "/weight~0~1kg", Rule: "type", Message: "Must be of type int"},
+				},
+			},
+		},
+		{
+			name:           "validation",
+			body:           `{"address": {"zip": 10001}}`,
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedResponse: httprouter.ErrorResponse{
+				Message: http.StatusText(http.StatusUnprocessableEntity),
+				Fields: []httprouter.FieldViolation{
+					{Field: "/name", Rule: "required", Message: "Name is required"},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != test.expectedStatus {
+				t.Fatalf("expected status code %d, got %d", test.expectedStatus, rec.Code)
+			}
+
+			var resp httprouter.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(test.expectedResponse, resp); diff != "" {
+				t.Error("unexpected response", diff)
+			}
+		})
+	}
+}
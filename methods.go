@@ -0,0 +1,73 @@
+package httprouter
+
+import "net/http"
+
+// GET registers HandlerFunc for GET requests at given path.
+func (r *Router) GET(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return r.Handler(http.MethodGet, path, handler, opts...)
+}
+
+// POST registers HandlerFunc for POST requests at given path.
+func (r *Router) POST(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return r.Handler(http.MethodPost, path, handler, opts...)
+}
+
+// PUT registers HandlerFunc for PUT requests at given path.
+func (r *Router) PUT(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return r.Handler(http.MethodPut, path, handler, opts...)
+}
+
+// PATCH registers HandlerFunc for PATCH requests at given path.
+func (r *Router) PATCH(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return r.Handler(http.MethodPatch, path, handler, opts...)
+}
+
+// DELETE registers HandlerFunc for DELETE requests at given path.
+func (r *Router) DELETE(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return r.Handler(http.MethodDelete, path, handler, opts...)
+}
+
+// HEAD registers HandlerFunc for HEAD requests at given path.
+func (r *Router) HEAD(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return r.Handler(http.MethodHead, path, handler, opts...)
+}
+
+// OPTIONS registers HandlerFunc for OPTIONS requests at given path.
+func (r *Router) OPTIONS(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return r.Handler(http.MethodOptions, path, handler, opts...)
+}
+
+// GET registers HandlerFunc for GET requests at given path relative to the group prefix.
+func (g *Group) GET(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return g.Handler(http.MethodGet, path, handler, opts...)
+}
+
+// POST registers HandlerFunc for POST requests at given path relative to the group prefix.
+func (g *Group) POST(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return g.Handler(http.MethodPost, path, handler, opts...)
+}
+
+// PUT registers HandlerFunc for PUT requests at given path relative to the group prefix.
+func (g *Group) PUT(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return g.Handler(http.MethodPut, path, handler, opts...)
+}
+
+// PATCH registers HandlerFunc for PATCH requests at given path relative to the group prefix.
+func (g *Group) PATCH(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return g.Handler(http.MethodPatch, path, handler, opts...)
+}
+
+// DELETE registers HandlerFunc for DELETE requests at given path relative to the group prefix.
+func (g *Group) DELETE(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return g.Handler(http.MethodDelete, path, handler, opts...)
+}
+
+// HEAD registers HandlerFunc for HEAD requests at given path relative to the group prefix.
+func (g *Group) HEAD(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return g.Handler(http.MethodHead, path, handler, opts...)
+}
+
+// OPTIONS registers HandlerFunc for OPTIONS requests at given path relative to the group prefix.
+func (g *Group) OPTIONS(path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	return g.Handler(http.MethodOptions, path, handler, opts...)
+}
@@ -0,0 +1,94 @@
+package httprouter_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestRouterMethods(t *testing.T) {
+	handler := func(w http.ResponseWriter, req *http.Request) error {
+		_, err := io.WriteString(w, req.Method+" "+httprouter.GetRoute(req.Context()))
+		return err
+	}
+
+	var chains int
+	counter := func(next httprouter.HandlerFunc) httprouter.HandlerFunc {
+		chains++
+		return next
+	}
+
+	router := httprouter.New()
+	router.GET("/get", handler)
+	router.POST("/post", handler)
+	router.PUT("/put", handler)
+	router.PATCH("/patch", handler)
+	router.DELETE("/delete", handler)
+	router.HEAD("/head", handler)
+	router.OPTIONS("/options", handler)
+	router.Handle([]string{http.MethodPost, http.MethodPut}, "/upsert", handler, httprouter.Use(counter))
+	router.Any("/any", handler)
+
+	api := router.Group("/api")
+	api.GET("/get", handler)
+	api.DELETE("/delete", handler)
+	api.Handle([]string{http.MethodGet, http.MethodHead}, "/status", handler)
+	api.Any("/any", handler)
+
+	if chains != 1 {
+		t.Errorf("expected middleware chain to be built once, got %d", chains)
+	}
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{method: http.MethodGet, path: "/get"},
+		{method: http.MethodPost, path: "/post"},
+		{method: http.MethodPut, path: "/put"},
+		{method: http.MethodPatch, path: "/patch"},
+		{method: http.MethodDelete, path: "/delete"},
+		{method: http.MethodHead, path: "/head"},
+		{method: http.MethodOptions, path: "/options"},
+		{method: http.MethodPost, path: "/upsert"},
+		{method: http.MethodPut, path: "/upsert"},
+		{method: http.MethodTrace, path: "/any"},
+		{method: http.MethodGet, path: "/api/get"},
+		{method: http.MethodDelete, path: "/api/delete"},
+		{method: http.MethodHead, path: "/api/status"},
+		{method: http.MethodConnect, path: "/api/any"},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.method+" "+test.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(test.method, test.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status code %d, got %d", http.StatusOK, rec.Code)
+			}
+
+			if expected := test.method + " " + test.path; rec.Body.String() != expected {
+				t.Errorf("expected body %q, got %q", expected, rec.Body.String())
+			}
+		})
+	}
+
+	var anyMethods []string
+	for _, route := range router.Routes() {
+		if route.Route == "/any" {
+			anyMethods = append(anyMethods, route.Method)
+		}
+	}
+
+	expected := []string{"CONNECT", "DELETE", "GET", "HEAD", "OPTIONS", "PATCH", "POST", "PUT", "TRACE"}
+	if diff := cmp.Diff(expected, anyMethods); diff != "" {
+		t.Error("unexpected methods of any route", diff)
+	}
+}
@@ -0,0 +1,154 @@
+package httprouter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestMount(t *testing.T) {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		data := httprouter.GetRouteData(req.Context())
+		fmt.Fprintf(w, "%s %s %s %s", req.Method, req.URL.Path, req.URL.RawPath, data.Mount)
+	})
+
+	sub := httprouter.New()
+	sub.HTTPHandler(http.MethodGet, "/users/:id", echo)
+
+	router := httprouter.New()
+	router.Mount("/echo", echo)
+	router.Mount("/sub/", sub)
+	router.Group("/tenants/:tenant").Mount("/files", echo)
+	router.Handler(http.MethodGet, "/echo/static", func(w http.ResponseWriter, req *http.Request) error {
+		_, err := fmt.Fprint(w, "static")
+		return err
+	})
+
+	tests := []struct {
+		name           string
+		method         string
+		target         string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "mount prefix",
+			method:         http.MethodGet,
+			target:         "/echo",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "GET /  /echo",
+		},
+		{
+			name:           "mount path",
+			method:         http.MethodDelete,
+			target:         "/echo/a/b",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "DELETE /a/b  /echo",
+		},
+		{
+			name:           "mount raw path",
+			method:         http.MethodPost,
+			target:         "/echo/a%2Fb",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "POST /a/b /a%2Fb /echo",
+		},
+		{
+			name:           "static route takes precedence",
+			method:         http.MethodGet,
+			target:         "/echo/static",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "static",
+		},
+		{
+			name:           "mounted router",
+			method:         http.MethodGet,
+			target:         "/sub/users/1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "GET /users/1  ",
+		},
+		{
+			name:           "mounted router not found",
+			method:         http.MethodGet,
+			target:         "/sub/unknown",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "mount with parameterised prefix",
+			method:         http.MethodPut,
+			target:         "/tenants/acme/files/report.pdf",
+			expectedStatus: http.StatusOK,
+			expectedBody:   "PUT /report.pdf  /tenants/:tenant/files",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(test.method, test.target, nil))
+
+			if rec.Code != test.expectedStatus {
+				t.Fatalf("expected status code %d, got %d", test.expectedStatus, rec.Code)
+			}
+
+			if test.expectedBody == "" {
+				return
+			}
+
+			if diff := cmp.Diff(test.expectedBody, rec.Body.String()); diff != "" {
+				t.Error("unexpected body", diff)
+			}
+		})
+	}
+}
+
+func TestMountRawPath(t *testing.T) {
+	echo := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "%s %s", req.URL.Path, req.URL.RawPath)
+	})
+
+	router := httprouter.New(httprouter.WithRawPath(true))
+	router.Mount("/t/:id", echo)
+
+	tests := []struct {
+		target       string
+		expectedBody string
+	}{
+		{target: "/t/x%2Fy/z", expectedBody: "/z "},
+		{target: "/t/x%2Fy/a%2Fb", expectedBody: "/a/b /a%2Fb"},
+		{target: "/t/x%2Fy", expectedBody: "/ "},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.target, nil))
+
+		if diff := cmp.Diff(test.expectedBody, rec.Body.String()); diff != "" {
+			t.Errorf("unexpected body for %s %s", test.target, diff)
+		}
+	}
+}
+
+func TestMountConflict(t *testing.T) {
+	router := httprouter.New(httprouter.WithDeferredErrors(true))
+	router.GET("/admin/*other", httprouter.NoopHandler)
+	router.Mount("/admin", http.NotFoundHandler())
+
+	var conflict *httprouter.WildcardConflictError
+	if err := router.Build(); !errors.As(err, &conflict) {
+		t.Fatalf("expected wildcard conflict, got %v", err)
+	}
+
+	expected := []httprouter.RouteInfo{
+		{Method: http.MethodGet, Route: "/admin/*other", Params: []string{"other"}},
+	}
+	if diff := cmp.Diff(expected, router.Routes()); diff != "" {
+		t.Error("expected failed mount to leave no routes", diff)
+	}
+}
@@ -0,0 +1,85 @@
+package httprouter
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Param parses the route parameter name from ctx with parse. A missing parameter yields
+// an Error with status 500, as it means the route does not define it, while a parse
+// failure yields an operational Error with status 400 that can be returned from handlers.
+func Param[T any](ctx context.Context, name string, parse func(value string) (T, error)) (T, error) {
+	var zero T
+
+	value, ok := GetRouteData(ctx).Params.Lookup(name)
+	if !ok {
+		return zero, NewError(http.StatusInternalServerError, Messagef("Missing parameter %q", name))
+	}
+
+	parsed, err := parse(value)
+	if err != nil {
+		return zero, NewError(
+			http.StatusBadRequest,
+			Messagef("Invalid value %q for parameter %q", value, name),
+			Operational(),
+			Cause(err),
+		)
+	}
+
+	return parsed, nil
+}
+
+// ParamInt parses the route parameter name from ctx as a base 10 integer.
+func ParamInt(ctx context.Context, name string) (int64, error) {
+	return Param(ctx, name, func(value string) (int64, error) {
+		return strconv.ParseInt(value, 10, 64)
+	})
+}
+
+// ParamUint parses the route parameter name from ctx as a base 10 unsigned integer.
+func ParamUint(ctx context.Context, name string) (uint64, error) {
+	return Param(ctx, name, func(value string) (uint64, error) {
+		return strconv.ParseUint(value, 10, 64)
+	})
+}
+
+// ParamFloat parses the route parameter name from ctx as a 64 bit float.
+func ParamFloat(ctx context.Context, name string) (float64, error) {
+	return Param(ctx, name, func(value string) (float64, error) {
+		return strconv.ParseFloat(value, 64)
+	})
+}
+
+// ParamBool parses the route parameter name from ctx as accepted by strconv.ParseBool.
+func ParamBool(ctx context.Context, name string) (bool, error) {
+	return Param(ctx, name, strconv.ParseBool)
+}
+
+// ParamTime parses the route parameter name from ctx as a time formatted with layout.
+func ParamTime(ctx context.Context, name, layout string) (time.Time, error) {
+	return Param(ctx, name, func(value string) (time.Time, error) {
+		return time.Parse(layout, value)
+	})
+}
+
+// ParamUUID parses the route parameter name from ctx as a UUID in its canonical
+// 8-4-4-4-12 hex form. The result converts directly to common UUID types, e.g. uuid.UUID(id).
+func ParamUUID(ctx context.Context, name string) ([16]byte, error) {
+	return Param(ctx, name, parseUUID)
+}
+
+func parseUUID(value string) ([16]byte, error) {
+	var id [16]byte
+	if !isUUID(value) {
+		return id, fmt.Errorf("invalid UUID format")
+	}
+
+	_, err := hex.Decode(id[:], []byte(strings.ReplaceAll(value, "-", "")))
+
+	return id, err
+}
@@ -0,0 +1,103 @@
+package httprouter_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestParamAccessors(t *testing.T) {
+	ctx := httprouter.WithRouteData(context.Background(), httprouter.RouteData{
+		Params: httprouter.Params{
+			{Key: "id", Value: "42"},
+			{Key: "on", Value: "true"},
+			{Key: "day", Value: "2021-11-18"},
+			{Key: "uuid", Value: "123e4567-e89b-12d3-a456-426614174000"},
+			{Key: "bad", Value: "forty-two"},
+		},
+	})
+
+	if id, err := httprouter.ParamInt(ctx, "id"); err != nil || id != 42 {
+		t.Errorf("expected 42, got %d, %v", id, err)
+	}
+
+	if on, err := httprouter.ParamBool(ctx, "on"); err != nil || !on {
+		t.Errorf("expected true, got %t, %v", on, err)
+	}
+
+	day, err := httprouter.ParamTime(ctx, "day", "2006-01-02")
+	if err != nil || !day.Equal(time.Date(2021, 11, 18, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected 2021-11-18, got %s, %v", day, err)
+	}
+
+	uuid, err := httprouter.ParamUUID(ctx, "uuid")
+	expectedUUID := [16]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00}
+	if err != nil || uuid != expectedUUID {
+		t.Errorf("expected %x, got %x, %v", expectedUUID, uuid, err)
+	}
+
+	tests := []struct {
+		name          string
+		parse         func() error
+		expectedError httprouter.Error
+	}{
+		{
+			name: "invalid int",
+			parse: func() error {
+				_, err := httprouter.ParamInt(ctx, "bad")
+				return err
+			},
+			expectedError: httprouter.Error{
+				Status:      http.StatusBadRequest,
+				Message:     `Invalid value "forty-two" for parameter "bad"`,
+				Operational: true,
+			},
+		},
+		{
+			name: "invalid uuid",
+			parse: func() error {
+				_, err := httprouter.ParamUUID(ctx, "id")
+				return err
+			},
+			expectedError: httprouter.Error{
+				Status:      http.StatusBadRequest,
+				Message:     `Invalid value "42" for parameter "id"`,
+				Operational: true,
+			},
+		},
+		{
+			name: "missing parameter",
+			parse: func() error {
+				_, err := httprouter.Param(ctx, "missing", func(value string) (string, error) {
+					return value, nil
+				})
+				return err
+			},
+			expectedError: httprouter.Error{
+				Status:  http.StatusInternalServerError,
+				Message: `Missing parameter "missing"`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var httpErr httprouter.Error
+			if err := test.parse(); !errors.As(err, &httpErr) {
+				t.Fatalf("expected httprouter.Error, got %v", err)
+			}
+
+			httpErr.Cause = nil
+			if diff := cmp.Diff(test.expectedError, httpErr); diff != "" {
+				t.Error("unexpected error", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package httprouter
+
+import (
+	"path"
+	"strings"
+)
+
+// isCleanPath reports whether p is free of duplicate slashes and dot segments.
+func isCleanPath(p string) bool {
+	for i := 0; i < len(p); i++ {
+		if p[i] != '/' {
+			continue
+		}
+
+		rest := p[i+1:]
+		switch {
+		case strings.HasPrefix(rest, "/"),
+			rest == ".", strings.HasPrefix(rest, "./"),
+			rest == "..", strings.HasPrefix(rest, "../"):
+			return false
+		}
+	}
+
+	return true
+}
+
+// cleanPath collapses duplicate slashes and resolves dot segments of p like path.Clean,
+// but keeps a trailing slash.
+func cleanPath(p string) string {
+	clean := path.Clean(p)
+	if clean != "/" && strings.HasSuffix(p, "/") {
+		clean += "/"
+	}
+
+	return clean
+}
+
+// searchCaseInsensitive looks up path ignoring the case of static segments, appending
+// the path of the matched route with its case to fixed.
+func (n *node) searchCaseInsensitive(path string, fixed []byte, rawPath bool) (*node, []byte) {
+	if len(path) == 0 {
+		if len(n.leafHandler) == 0 {
+			return nil, nil
+		}
+
+		return n, fixed
+	}
+
+	firstChar := toLower(path[0])
+	for i, staticIndex := range n.staticIndices {
+		// Children may differ only in case, so all of them are checked.
+		if toLower(staticIndex) != firstChar {
+			continue
+		}
+
+		child := n.staticChild[i]
+		childPathLen := len(child.path)
+		if len(path) >= childPathLen && strings.EqualFold(child.path, path[:childPathLen]) {
+			if found, out := child.searchCaseInsensitive(path[childPathLen:], append(fixed, child.path...), rawPath); found != nil {
+				return found, out
+			}
+		}
+	}
+
+	nextSlash := strings.IndexByte(path, '/')
+	if nextSlash < 0 {
+		nextSlash = len(path)
+	}
+
+	if thisToken := path[:nextSlash]; len(thisToken) > 0 {
+		for _, wildcardChild := range n.wildcardChildren {
+			if wildcardChild.constraint != nil && !wildcardChild.constraint.matchToken(thisToken, rawPath) {
+				continue
+			}
+
+			if found, out := wildcardChild.searchCaseInsensitive(path[nextSlash:], append(fixed, thisToken...), rawPath); found != nil {
+				return found, out
+			}
+		}
+	}
+
+	if n.catchAllChild != nil && len(n.catchAllChild.leafHandler) != 0 {
+		return n.catchAllChild, append(fixed, path...)
+	}
+
+	return nil, nil
+}
+
+func toLower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+
+	return c
+}
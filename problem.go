@@ -0,0 +1,133 @@
+package httprouter
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemResponse is the body written by ProblemErrorHandler, as defined by RFC 9457.
+type ProblemResponse struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	// Extensions are the members besides the standard ones.
+	Extensions map[string]interface{}
+}
+
+var problemMembers = []string{"type", "title", "status", "detail", "instance"}
+
+func (p ProblemResponse) MarshalJSON() ([]byte, error) {
+	members := make(map[string]interface{}, len(p.Extensions)+len(problemMembers))
+	for key, value := range p.Extensions {
+		members[key] = value
+	}
+
+	members["type"] = p.Type
+	members["title"] = p.Title
+	members["status"] = p.Status
+
+	if p.Detail != "" {
+		members["detail"] = p.Detail
+	} else {
+		delete(members, "detail")
+	}
+
+	if p.Instance != "" {
+		members["instance"] = p.Instance
+	} else {
+		delete(members, "instance")
+	}
+
+	return json.Marshal(members)
+}
+
+func (p *ProblemResponse) UnmarshalJSON(data []byte) error {
+	var standard struct {
+		Type     string `json:"type"`
+		Title    string `json:"title"`
+		Status   int    `json:"status"`
+		Detail   string `json:"detail"`
+		Instance string `json:"instance"`
+	}
+
+	if err := json.Unmarshal(data, &standard); err != nil {
+		return err
+	}
+
+	var members map[string]interface{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	for _, member := range problemMembers {
+		delete(members, member)
+	}
+
+	if len(members) == 0 {
+		members = nil
+	}
+
+	*p = ProblemResponse{
+		Type:       standard.Type,
+		Title:      standard.Title,
+		Status:     standard.Status,
+		Detail:     standard.Detail,
+		Instance:   standard.Instance,
+		Extensions: members,
+	}
+
+	return nil
+}
+
+// ProblemErrorHandler writes err as application/problem+json. The title is the error message,
+// the type defaults to "about:blank" and the instance is the request path. The error code is
+// included in the "code" member, the field violations in the "fields" member and, when verbose,
+// the cause in the "debug" member.
+func ProblemErrorHandler(w http.ResponseWriter, req *http.Request, verbose bool, err Error) {
+	err.ApplyHeader(w)
+
+	if !writesErrorBody(req) {
+		w.WriteHeader(err.Status)
+		return
+	}
+
+	problemType := err.Type
+	if problemType == "" {
+		problemType = "about:blank"
+	}
+
+	resp := ProblemResponse{
+		Type:       problemType,
+		Title:      err.Message,
+		Status:     err.Status,
+		Detail:     err.Detail,
+		Instance:   req.URL.Path,
+		Extensions: err.Extensions(),
+	}
+
+	if err.Code != "" || len(err.Fields()) != 0 || (verbose && err.Cause != nil) {
+		resp.Extensions = make(map[string]interface{}, len(resp.Extensions)+3)
+		for key, value := range err.Extensions() {
+			resp.Extensions[key] = value
+		}
+
+		if err.Code != "" {
+			resp.Extensions["code"] = err.Code
+		}
+
+		if len(err.Fields()) != 0 {
+			resp.Extensions["fields"] = err.Fields()
+		}
+
+		if verbose && err.Cause != nil {
+			resp.Extensions["debug"] = err.Cause.Error()
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(err.Status)
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,109 @@
+package httprouter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestProblemErrorHandler(t *testing.T) {
+	router := httprouter.New(
+		httprouter.WithErrorHandler(httprouter.ProblemErrorHandler),
+		httprouter.WithVerbose(true),
+	)
+
+	router.GET("/accounts/:id", func(w http.ResponseWriter, req *http.Request) error {
+		return httprouter.NewError(
+			http.StatusForbidden,
+			httprouter.Type("https://example.com/probs/out-of-credit"),
+			httprouter.Message("You do not have enough credit."),
+			httprouter.Detail("Your current balance is 30, but that costs 50."),
+			httprouter.Extension("balance", 30),
+			httprouter.Extension("status", 200),
+			httprouter.Cause(errors.New("balance check failed")),
+		)
+	})
+
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		expectedStatus   int
+		expectedResponse *httprouter.ProblemResponse
+	}{
+		{
+			name:           "problem",
+			method:         http.MethodGet,
+			path:           "/accounts/12345",
+			expectedStatus: http.StatusForbidden,
+			expectedResponse: &httprouter.ProblemResponse{
+				Type:     "https://example.com/probs/out-of-credit",
+				Title:    "You do not have enough credit.",
+				Status:   http.StatusForbidden,
+				Detail:   "Your current balance is 30, but that costs 50.",
+				Instance: "/accounts/12345",
+				Extensions: map[string]interface{}{
+					"balance": float64(30),
+					"debug":   "balance check failed",
+				},
+			},
+		},
+		{
+			name:           "not found",
+			method:         http.MethodGet,
+			path:           "/unknown",
+			expectedStatus: http.StatusNotFound,
+			expectedResponse: &httprouter.ProblemResponse{
+				Type:     "about:blank",
+				Title:    http.StatusText(http.StatusNotFound),
+				Status:   http.StatusNotFound,
+				Instance: "/unknown",
+			},
+		},
+		{
+			name:           "no body",
+			method:         http.MethodHead,
+			path:           "/accounts/12345",
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(test.method, test.path, nil))
+
+			if rec.Code != test.expectedStatus {
+				t.Fatalf("expected status code %d, got %d", test.expectedStatus, rec.Code)
+			}
+
+			if test.expectedResponse == nil {
+				if rec.Body.Len() != 0 {
+					t.Errorf("expected no body, got %q", rec.Body.String())
+				}
+
+				return
+			}
+
+			if contentType := rec.Header().Get("Content-Type"); contentType != "application/problem+json" {
+				t.Errorf("expected problem content type, got %q", contentType)
+			}
+
+			var resp httprouter.ProblemResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(test.expectedResponse, &resp); diff != "" {
+				t.Error("unexpected response", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package httprouter
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// InvalidPathError is returned when a route is registered with a malformed path.
+type InvalidPathError struct {
+	Path   string
+	Reason string
+}
+
+func (e *InvalidPathError) Error() string {
+	return fmt.Sprintf("invalid path %q: %s", e.Path, e.Reason)
+}
+
+// InvalidRouteError is returned when a route is registered without methods or with
+// an invalid option.
+type InvalidRouteError struct {
+	Path   string
+	Reason string
+}
+
+func (e *InvalidRouteError) Error() string {
+	return fmt.Sprintf("invalid route %s: %s", e.Path, e.Reason)
+}
+
+// DuplicateRouteError is returned when a method is registered twice for the same route.
+type DuplicateRouteError struct {
+	Method   string
+	Path     string
+	Existing string
+}
+
+func (e *DuplicateRouteError) Error() string {
+	return fmt.Sprintf("%s %s conflicts with existing route %s", e.Method, e.Path, e.Existing)
+}
+
+// WildcardConflictError is returned when parameter or catch-all names of a route
+// are ambiguous with the names of an existing route.
+type WildcardConflictError struct {
+	Path     string
+	Existing string
+}
+
+func (e *WildcardConflictError) Error() string {
+	return fmt.Sprintf("wildcards of %s are ambiguous with existing route %s", e.Path, e.Existing)
+}
+
+// DuplicateNameError is returned when a route name is already used by another route.
+type DuplicateNameError struct {
+	Name     string
+	Path     string
+	Existing string
+}
+
+func (e *DuplicateNameError) Error() string {
+	return fmt.Sprintf("route name %q of %s already used by %s", e.Name, e.Path, e.Existing)
+}
+
+// RegistrationErrors aggregates errors collected by a Router created WithDeferredErrors.
+// Individual errors can be inspected with errors.As and errors.Is.
+type RegistrationErrors []error
+
+func (e RegistrationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d route registration errors: %s", len(e), strings.Join(messages, "; "))
+}
+
+func (e RegistrationErrors) Unwrap() []error {
+	return e
+}
+
+func (e RegistrationErrors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e RegistrationErrors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func validatePath(path string) error {
+	switch {
+	case len(path) == 0:
+		return &InvalidPathError{Path: path, Reason: "path must be non empty"}
+	case path[0] != '/':
+		return &InvalidPathError{Path: path, Reason: "path must start with slash"}
+	}
+
+	return nil
+}
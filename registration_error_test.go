@@ -0,0 +1,267 @@
+package httprouter_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestRouterTryHandler(t *testing.T) {
+	router := httprouter.New()
+	router.Handler(http.MethodGet, "/users/:id", httprouter.NoopHandler)
+	router.Handler(http.MethodGet, "/files/*path", httprouter.NoopHandler)
+
+	tests := []struct {
+		name          string
+		path          string
+		expectedError error
+	}{
+		{
+			name:          "empty path",
+			path:          "",
+			expectedError: &httprouter.InvalidPathError{Path: "", Reason: "path must be non empty"},
+		},
+		{
+			name:          "relative path",
+			path:          "users",
+			expectedError: &httprouter.InvalidPathError{Path: "users", Reason: "path must start with slash"},
+		},
+		{
+			name:          "slash after catch-all",
+			path:          "/static/*path/more",
+			expectedError: &httprouter.InvalidPathError{Path: "/static/*path/more", Reason: "/ after catch-all"},
+		},
+		{
+			name: "duplicate route",
+			path: "/users/:id",
+			expectedError: &httprouter.DuplicateRouteError{
+				Method:   http.MethodGet,
+				Path:     "/users/:id",
+				Existing: "/users/:id",
+			},
+		},
+		{
+			name:          "ambiguous parameter",
+			path:          "/users/:user_id",
+			expectedError: &httprouter.WildcardConflictError{Path: "/users/:user_id", Existing: "/users/:id"},
+		},
+		{
+			name:          "overlapping catch-all",
+			path:          "/files/*name",
+			expectedError: &httprouter.WildcardConflictError{Path: "/files/*name", Existing: "/files/*path"},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := router.TryHandler(http.MethodGet, test.path, httprouter.NoopHandler)
+			if diff := cmp.Diff(test.expectedError, err); diff != "" {
+				t.Error("unexpected error", diff)
+			}
+		})
+	}
+}
+
+func TestRouterBuild(t *testing.T) {
+	router := httprouter.New(httprouter.WithDeferredErrors(true))
+	router.Handler(http.MethodGet, "/users/:id", httprouter.NoopHandler, httprouter.Name("user"))
+	router.Handler(http.MethodGet, "/users/:id", httprouter.NoopHandler)
+	router.Group("/users").Handler(http.MethodPost, "/:user_id", httprouter.NoopHandler)
+	router.Handler(http.MethodGet, "/accounts/:id", httprouter.NoopHandler, httprouter.Name("user"))
+
+	err := router.Build()
+
+	var errs httprouter.RegistrationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected RegistrationErrors, got %v", err)
+	}
+
+	if len(errs) != 3 {
+		t.Errorf("expected 3 errors, got %d: %v", len(errs), err)
+	}
+
+	var duplicateErr *httprouter.DuplicateRouteError
+	if !errors.As(err, &duplicateErr) || duplicateErr.Path != "/users/:id" {
+		t.Errorf("expected DuplicateRouteError, got %v", err)
+	}
+
+	var wildcardErr *httprouter.WildcardConflictError
+	if !errors.As(err, &wildcardErr) || wildcardErr.Path != "/users/:user_id" {
+		t.Errorf("expected WildcardConflictError, got %v", err)
+	}
+
+	var nameErr *httprouter.DuplicateNameError
+	if !errors.As(err, &nameErr) || nameErr.Name != "user" {
+		t.Errorf("expected DuplicateNameError, got %v", err)
+	}
+
+	if err := httprouter.New().Build(); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestRouterHandleErrors(t *testing.T) {
+	router := httprouter.New(httprouter.WithDeferredErrors(true))
+	api := router.Group("/api")
+
+	tests := []struct {
+		name           string
+		handle         func() []*httprouter.Route
+		expectedRoutes int
+		invalidRoute   bool
+	}{
+		{
+			name: "router invalid path",
+			handle: func() []*httprouter.Route {
+				return router.Handle([]string{http.MethodGet, http.MethodPost}, "users", httprouter.NoopHandler)
+			},
+			expectedRoutes: 2,
+		},
+		{
+			name: "group invalid path",
+			handle: func() []*httprouter.Route {
+				return api.Handle([]string{http.MethodGet, http.MethodPost}, "users", httprouter.NoopHandler)
+			},
+			expectedRoutes: 2,
+		},
+		{
+			name: "router no methods",
+			handle: func() []*httprouter.Route {
+				return router.Handle(nil, "/users", httprouter.NoopHandler)
+			},
+			invalidRoute: true,
+		},
+		{
+			name: "group no methods",
+			handle: func() []*httprouter.Route {
+				return api.Handle([]string{}, "/users", httprouter.NoopHandler)
+			},
+			invalidRoute: true,
+		},
+		{
+			name: "empty name",
+			handle: func() []*httprouter.Route {
+				return []*httprouter.Route{router.GET("/users", httprouter.NoopHandler, httprouter.Name(""))}
+			},
+			expectedRoutes: 1,
+			invalidRoute:   true,
+		},
+		{
+			name: "non positive timeout",
+			handle: func() []*httprouter.Route {
+				return api.Handle([]string{http.MethodGet}, "/users", httprouter.NoopHandler, httprouter.Timeout(0))
+			},
+			expectedRoutes: 1,
+			invalidRoute:   true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			if routes := test.handle(); len(routes) != test.expectedRoutes {
+				t.Errorf("expected %d routes, got %d", test.expectedRoutes, len(routes))
+			}
+		})
+	}
+
+	var errs httprouter.RegistrationErrors
+	if err := router.Build(); !errors.As(err, &errs) || len(errs) != len(tests) {
+		t.Fatalf("expected %d registration errors, got %v", len(tests), err)
+	}
+
+	for i, err := range errs {
+		var invalidPath *httprouter.InvalidPathError
+		var invalidRoute *httprouter.InvalidRouteError
+		switch {
+		case tests[i].invalidRoute && !errors.As(err, &invalidRoute):
+			t.Errorf("%s: expected invalid route error, got %v", tests[i].name, err)
+		case !tests[i].invalidRoute && !errors.As(err, &invalidPath):
+			t.Errorf("%s: expected invalid path error, got %v", tests[i].name, err)
+		}
+	}
+
+	if routes := router.Routes(); len(routes) != 0 {
+		t.Errorf("expected no routes, got %+v", routes)
+	}
+}
+
+func TestRouterInvalidGroupAndHost(t *testing.T) {
+	router := httprouter.New(httprouter.WithDeferredErrors(true))
+	router.Group("api").GET("/users", httprouter.NoopHandler)
+	router.Group("/api").Group("").GET("/accounts", httprouter.NoopHandler)
+	router.Host("").GET("/status", httprouter.NoopHandler)
+	router.Host("api..example.com").GET("/status", httprouter.NoopHandler)
+
+	var errs httprouter.RegistrationErrors
+	if err := router.Build(); !errors.As(err, &errs) || len(errs) != 4 {
+		t.Fatalf("expected 4 registration errors, got %v", err)
+	}
+
+	for _, err := range errs {
+		var invalid *httprouter.InvalidPathError
+		if !errors.As(err, &invalid) {
+			t.Errorf("expected invalid path error, got %v", err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/status", nil)
+	req.Host = ""
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("expected routes of invalid hosts not to be served, got status code %d", rec.Code)
+	}
+}
+
+func TestRouterDuplicateNameOption(t *testing.T) {
+	router := httprouter.New()
+	router.GET("/a", httprouter.NoopHandler, httprouter.Name("x"))
+
+	register := map[string]func() error{
+		"router": func() error {
+			_, err := router.TryHandler(http.MethodGet, "/b", httprouter.NoopHandler, httprouter.Name("x"))
+			return err
+		},
+		"group": func() error {
+			_, err := router.Group("/api").TryHandler(http.MethodGet, "/b", httprouter.NoopHandler, httprouter.Name("x"))
+			return err
+		},
+	}
+
+	for name, fn := range register {
+		fn := fn
+		t.Run(name, func(t *testing.T) {
+			var nameErr *httprouter.DuplicateNameError
+			if err := fn(); !errors.As(err, &nameErr) || nameErr.Existing != "/a" {
+				t.Errorf("expected DuplicateNameError, got %v", err)
+			}
+		})
+	}
+
+	t.Run("handle", func(t *testing.T) {
+		deferred := httprouter.New(httprouter.WithDeferredErrors(true))
+		deferred.GET("/a", httprouter.NoopHandler, httprouter.Name("x"))
+		deferred.Handle([]string{http.MethodGet, http.MethodPost}, "/b", httprouter.NoopHandler, httprouter.Name("x"))
+
+		var nameErr *httprouter.DuplicateNameError
+		if err := deferred.Build(); !errors.As(err, &nameErr) {
+			t.Errorf("expected DuplicateNameError, got %v", err)
+		}
+
+		if routes := deferred.Routes(); len(routes) != 1 {
+			t.Errorf("expected route with duplicate name not to be registered, got %+v", routes)
+		}
+	})
+
+	if routes := router.Routes(); len(routes) != 1 {
+		t.Errorf("expected routes with duplicate name not to be registered, got %+v", routes)
+	}
+}
@@ -2,6 +2,7 @@ package httprouter
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -92,3 +93,98 @@ func (r *responseWriterFlusher) Flush() {
 	flusher := r.delegate.(http.Flusher)
 	flusher.Flush()
 }
+
+// headResponseWriter discards the response body of HEAD requests served by GET handlers,
+// while tracking its size to report it as Content-Length. Writing the header is deferred
+// until the handler returns, unless the response is flushed.
+type headResponseWriter struct {
+	delegate    ResponseWriter
+	statusCode  int
+	size        int
+	wroteHeader bool
+}
+
+func newHeadResponseWriter(delegate http.ResponseWriter) *headResponseWriter {
+	rw, ok := delegate.(ResponseWriter)
+	if !ok {
+		rw = NewResponseWriter(delegate)
+	}
+
+	return &headResponseWriter{delegate: rw}
+}
+
+func (h *headResponseWriter) Header() http.Header {
+	return h.delegate.Header()
+}
+
+// Write implements http.ResponseWriter
+func (h *headResponseWriter) Write(data []byte) (int, error) {
+	h.size += len(data)
+
+	return len(data), nil
+}
+
+// WriteHeader implements http.ResponseWriter
+func (h *headResponseWriter) WriteHeader(statusCode int) {
+	if h.statusCode == 0 {
+		h.statusCode = statusCode
+	}
+}
+
+// Push implements http.Pusher
+func (h *headResponseWriter) Push(target string, opts *http.PushOptions) error {
+	return h.delegate.Push(target, opts)
+}
+
+// Flush implements http.Flusher
+func (h *headResponseWriter) Flush() {
+	h.writeHeader()
+
+	if flusher, ok := h.delegate.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Size returns total number of bytes written to response, although they were discarded
+func (h *headResponseWriter) Size() int {
+	return h.size
+}
+
+// StatusCode returns http status code set by WriteHeader
+func (h *headResponseWriter) StatusCode() int {
+	if h.statusCode == 0 {
+		return http.StatusOK
+	}
+
+	return h.statusCode
+}
+
+// Latency records time since the underlying ResponseWriter was created
+func (h *headResponseWriter) Latency() time.Duration {
+	return h.delegate.Latency()
+}
+
+func (h *headResponseWriter) writeHeader() {
+	if h.wroteHeader {
+		return
+	}
+	h.wroteHeader = true
+
+	h.delegate.WriteHeader(h.StatusCode())
+}
+
+// finish sets Content-Length to the size of the discarded body, unless it was set by
+// the handler, and writes the header.
+func (h *headResponseWriter) finish() {
+	if h.wroteHeader {
+		return
+	}
+
+	status := h.StatusCode()
+	if h.Header().Get("Content-Length") == "" && status >= http.StatusOK &&
+		status != http.StatusNoContent && status != http.StatusNotModified {
+		h.Header().Set("Content-Length", strconv.Itoa(h.size))
+	}
+
+	h.writeHeader()
+}
@@ -0,0 +1,199 @@
+package httprouter
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Route is a route registered with Handler. It's configured with RouteOpt on registration and
+// immutable afterwards, as it's shared with concurrent lookups.
+type Route struct {
+	router     *Router
+	host       *host
+	method     string
+	path       string
+	name       string
+	mount      string
+	middleware int
+	meta       Metadata
+	summary    string
+	timeout    time.Duration
+}
+
+// RouteInfo describes a registered route, as returned from Router.Routes.
+type RouteInfo struct {
+	// Host is the host pattern passed to Router.Host, or empty if the route matches any host.
+	Host   string
+	Method string
+	Route  string
+	// Params lists the names of the route parameters, the ones captured from the host
+	// followed by the ones of the path in order, with "*" standing for an unnamed catch-all.
+	Params []string
+	Name   string
+	// Mount is the prefix passed to Router.Mount when the route was registered by it.
+	Mount string
+	// Middleware is the number of middleware wrapping the handler, including global middleware
+	// and the one added by the Timeout option.
+	Middleware int
+	// Meta holds a copy of the metadata attached with the Meta option.
+	Meta Metadata
+	// Summary is the description set with the Summary option.
+	Summary string
+	// Timeout is the deadline set with the Timeout option, or zero if there is none.
+	Timeout time.Duration
+}
+
+// Routes lists all registered routes ordered by route and method.
+func (r *Router) Routes() []RouteInfo {
+	var routes []RouteInfo
+	// The parameters of the host of the tree being walked, which precede the path parameters.
+	var hostParams []string
+	collect := func(n *node) {
+		params := append([]string(nil), hostParams...)
+		for _, name := range n.leafWildcardNames {
+			if name == "" {
+				name = "*"
+			}
+			params = append(params, name)
+		}
+
+		for method, route := range n.leafRoute {
+			if method == http.MethodHead && n.implicitHead {
+				continue
+			}
+
+			var host string
+			if route.host != nil {
+				host = route.host.pattern
+			}
+
+			routes = append(routes, RouteInfo{
+				Host:       host,
+				Method:     route.method,
+				Route:      route.path,
+				Params:     params,
+				Name:       route.name,
+				Mount:      route.mount,
+				Middleware: route.middleware,
+				Meta:       route.meta.clone(),
+				Summary:    route.summary,
+				Timeout:    route.timeout,
+			})
+		}
+	}
+
+	t := r.load()
+	t.root.walk(collect)
+	for _, ht := range t.hosts {
+		hostParams = hostParams[:0]
+		for _, label := range ht.host.labels {
+			if label[0] == ':' {
+				hostParams = append(hostParams, label[1:])
+			}
+		}
+
+		ht.root.walk(collect)
+	}
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Host != routes[j].Host {
+			return routes[i].Host < routes[j].Host
+		}
+
+		if routes[i].Route != routes[j].Route {
+			return routes[i].Route < routes[j].Route
+		}
+
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes
+}
+
+// Method returns the method the route is registered for.
+func (rt *Route) Method() string {
+	return rt.method
+}
+
+// Path returns the path pattern of the route.
+func (rt *Route) Path() string {
+	return rt.path
+}
+
+// Name returns the name assigned with the Name option, or an empty string if there is none.
+func (rt *Route) Name() string {
+	return rt.name
+}
+
+// URL builds the path of the route registered with given name, substituting its parameters
+// and catch-all with values from params. Values are escaped, catch-all values keep their slashes.
+func (r *Router) URL(name string, params map[string]string) (string, error) {
+	r.mu.Lock()
+	rt, ok := r.names[name]
+	r.mu.Unlock()
+
+	if !ok {
+		return "", fmt.Errorf("route %q not found", name)
+	}
+
+	return buildPath(rt.path, params)
+}
+
+func buildPath(pattern string, params map[string]string) (string, error) {
+	var b strings.Builder
+	b.Grow(len(pattern))
+
+	path := pattern
+	for len(path) > 0 {
+		b.WriteByte('/')
+		path = path[1:]
+
+		switch {
+		case strings.HasPrefix(path, ":"):
+			name, expr, end, _ := splitParam(path)
+			path = path[end:]
+
+			value, ok := params[name]
+			if !ok || value == "" {
+				return "", fmt.Errorf("missing value for parameter %q of route %s", name, pattern)
+			}
+
+			if expr != "" {
+				if c, err := newConstraint(expr); err == nil && !c.match(value) {
+					return "", fmt.Errorf("value %q of parameter %q does not satisfy constraint <%s> of route %s",
+						value, name, expr, pattern)
+				}
+			}
+
+			b.WriteString(url.PathEscape(value))
+		case strings.HasPrefix(path, "*"):
+			name := path[1:]
+			if name == "" {
+				name = "*"
+			}
+			path = ""
+
+			value := strings.TrimPrefix(params[name], "/")
+			for i, part := range strings.Split(value, "/") {
+				if i > 0 {
+					b.WriteByte('/')
+				}
+				b.WriteString(url.PathEscape(part))
+			}
+		default:
+			end := strings.IndexByte(path, '/')
+			if end < 0 {
+				end = len(path)
+			}
+
+			b.WriteString(strings.TrimPrefix(path[:end], `\`))
+			path = path[end:]
+		}
+	}
+
+	return b.String(), nil
+}
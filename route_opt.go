@@ -0,0 +1,102 @@
+package httprouter
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RouteOpt configures a route registered with Handler or HTTPHandler.
+type RouteOpt func(c *routeConfig)
+
+// Use wraps the handler of the route in middleware, applied after the global and group middleware.
+func Use(middleware ...Middleware) RouteOpt {
+	return func(c *routeConfig) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// Name assigns a name to the route so its URL can be generated with Router.URL.
+// Names must be unique within a Router and non empty.
+func Name(name string) RouteOpt {
+	return func(c *routeConfig) {
+		if name == "" && c.invalid == "" {
+			c.invalid = "route name must be non empty"
+		}
+
+		c.name = name
+	}
+}
+
+// Meta attaches value under key to the route, to be read from RouteData.Meta by middleware
+// and handlers. Keys should be of unexported types like context keys to avoid collisions.
+func Meta(key, value interface{}) RouteOpt {
+	return func(c *routeConfig) {
+		if c.meta == nil {
+			c.meta = make(Metadata)
+		}
+
+		c.meta[key] = value
+	}
+}
+
+// Summary sets a short human readable description of the route, listed by Router.Routes.
+func Summary(summary string) RouteOpt {
+	return func(c *routeConfig) {
+		c.summary = summary
+	}
+}
+
+// Timeout sets a deadline on the request context of the route, which bounds the group and
+// route middleware along with the handler. Handlers are expected to honor the context.
+// The timeout must be positive.
+func Timeout(timeout time.Duration) RouteOpt {
+	return func(c *routeConfig) {
+		if timeout <= 0 && c.invalid == "" {
+			c.invalid = "route timeout must be positive"
+		}
+
+		c.timeout = timeout
+	}
+}
+
+type routeConfig struct {
+	middleware []Middleware
+	name       string
+	meta       Metadata
+	summary    string
+	timeout    time.Duration
+	// invalid describes the first invalid option, if any.
+	invalid string
+}
+
+func newRouteConfig(opts []RouteOpt) routeConfig {
+	var c routeConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
+
+// middlewareCount returns the number of middleware wrapping a handler configured with c,
+// given the number of global middleware.
+func (c routeConfig) middlewareCount(global int) int {
+	count := global + len(c.middleware)
+	if c.timeout > 0 {
+		count++
+	}
+
+	return count
+}
+
+func timeoutMiddleware(timeout time.Duration) Middleware {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) error {
+			ctx, cancel := context.WithTimeout(req.Context(), timeout)
+			defer cancel()
+
+			return next(w, req.WithContext(ctx))
+		}
+	}
+}
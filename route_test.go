@@ -0,0 +1,224 @@
+package httprouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/goes-funky/httprouter"
+)
+
+func TestRouterURL(t *testing.T) {
+	router := httprouter.New()
+	router.Handler(http.MethodGet, "/users/:id", httprouter.NoopHandler, httprouter.Name("user.show"))
+	router.Handler(http.MethodGet, "/users/:user_id/files/*path", httprouter.NoopHandler, httprouter.Name("user.file"))
+	router.Group("/static").Handler(http.MethodGet, "/*", httprouter.NoopHandler, httprouter.Name("static"))
+	router.Handler(http.MethodGet, `/\:literal/:id/`, httprouter.NoopHandler, httprouter.Name("literal"))
+
+	tests := []struct {
+		name          string
+		route         string
+		params        map[string]string
+		expectedURL   string
+		expectedError bool
+	}{
+		{
+			name:        "parameter",
+			route:       "user.show",
+			params:      map[string]string{"id": "42"},
+			expectedURL: "/users/42",
+		},
+		{
+			name:        "escaped parameter",
+			route:       "user.show",
+			params:      map[string]string{"id": "a/b c"},
+			expectedURL: "/users/a%2Fb%20c",
+		},
+		{
+			name:        "named catch-all",
+			route:       "user.file",
+			params:      map[string]string{"user_id": "1", "path": "docs/a b.txt"},
+			expectedURL: "/users/1/files/docs/a%20b.txt",
+		},
+		{
+			name:        "unnamed catch-all in group",
+			route:       "static",
+			params:      map[string]string{"*": "css/site.css"},
+			expectedURL: "/static/css/site.css",
+		},
+		{
+			name:        "escaped static segment with trailing slash",
+			route:       "literal",
+			params:      map[string]string{"id": "1"},
+			expectedURL: "/:literal/1/",
+		},
+		{
+			name:          "missing parameter",
+			route:         "user.show",
+			expectedError: true,
+		},
+		{
+			name:          "unknown route",
+			route:         "unknown",
+			expectedError: true,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			url, err := router.URL(test.route, test.params)
+			if test.expectedError {
+				if err == nil {
+					t.Errorf("expected error, got %q", url)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if url != test.expectedURL {
+				t.Errorf("expected url %q, got %q", test.expectedURL, url)
+			}
+		})
+	}
+}
+
+func TestRouterRoutes(t *testing.T) {
+	noop := func(next httprouter.HandlerFunc) httprouter.HandlerFunc {
+		return next
+	}
+
+	router := httprouter.New(httprouter.WithMiddleware(noop))
+	router.Handler(http.MethodPost, "/users", httprouter.NoopHandler)
+	router.Handler(http.MethodGet, "/users/:id", httprouter.NoopHandler,
+		httprouter.Use(noop),
+		httprouter.Name("user.show"),
+		httprouter.Summary("Show user"),
+		httprouter.Timeout(time.Second),
+	)
+	router.Handler(http.MethodDelete, "/users/:id", httprouter.NoopHandler)
+	router.Handler(http.MethodGet, "/users", httprouter.NoopHandler)
+	router.Group("/files", noop).Handler(http.MethodGet, "/*path", httprouter.NoopHandler)
+	router.Host(":tenant.example.com").Handler(http.MethodGet, "/users/:id", httprouter.NoopHandler)
+
+	expected := []httprouter.RouteInfo{
+		{Method: http.MethodGet, Route: "/files/*path", Params: []string{"path"}, Middleware: 2},
+		{Method: http.MethodGet, Route: "/users", Middleware: 1},
+		{Method: http.MethodPost, Route: "/users", Middleware: 1},
+		{Method: http.MethodDelete, Route: "/users/:id", Params: []string{"id"}, Middleware: 1},
+		{
+			Method:     http.MethodGet,
+			Route:      "/users/:id",
+			Params:     []string{"id"},
+			Name:       "user.show",
+			Middleware: 3,
+			Summary:    "Show user",
+			Timeout:    time.Second,
+		},
+		{
+			Host:       ":tenant.example.com",
+			Method:     http.MethodGet,
+			Route:      "/users/:id",
+			Params:     []string{"tenant", "id"},
+			Middleware: 1,
+		},
+	}
+
+	if diff := cmp.Diff(expected, router.Routes()); diff != "" {
+		t.Error("unexpected routes", diff)
+	}
+}
+
+func TestRouteMeta(t *testing.T) {
+	type scopesKey struct{}
+	type teamKey struct{}
+
+	var scopes []string
+	auth := func(next httprouter.HandlerFunc) httprouter.HandlerFunc {
+		return func(w http.ResponseWriter, req *http.Request) error {
+			scopes, _ = httprouter.MetaValue[[]string](req.Context(), scopesKey{})
+			return next(w, req)
+		}
+	}
+
+	router := httprouter.New(httprouter.WithMiddleware(auth))
+	router.Handler(http.MethodGet, "/users/:id", httprouter.NoopHandler,
+		httprouter.Meta(scopesKey{}, []string{"users:read"}),
+		httprouter.Meta(teamKey{}, "identity"),
+	)
+	router.Handler(http.MethodGet, "/health", httprouter.NoopHandler)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	if diff := cmp.Diff([]string{"users:read"}, scopes); diff != "" {
+		t.Error("unexpected scopes", diff)
+	}
+
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	if scopes != nil {
+		t.Errorf("expected no scopes, got %v", scopes)
+	}
+
+	routes := router.Routes()
+	if team := routes[1].Meta.Get(teamKey{}); team != "identity" {
+		t.Errorf("expected team metadata in routes, got %v", team)
+	}
+}
+
+func TestRouteTimeout(t *testing.T) {
+	var remaining time.Duration
+	handler := func(w http.ResponseWriter, req *http.Request) error {
+		if deadline, ok := req.Context().Deadline(); ok {
+			remaining = time.Until(deadline)
+		}
+
+		return nil
+	}
+
+	router := httprouter.New()
+	router.Group("/api").Handler(http.MethodGet, "/slow", handler, httprouter.Timeout(time.Minute))
+	router.Handler(http.MethodGet, "/fast", handler)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+
+	if remaining <= 0 || remaining > time.Minute {
+		t.Errorf("expected deadline within a minute, got %v", remaining)
+	}
+
+	remaining = 0
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+	if remaining != 0 {
+		t.Errorf("expected no deadline, got %v", remaining)
+	}
+}
+
+func TestRouteMetaPerMethod(t *testing.T) {
+	type tierKey struct{}
+
+	router := httprouter.New()
+	routes := router.Handle([]string{http.MethodGet, http.MethodPost}, "/users", httprouter.NoopHandler,
+		httprouter.Meta(tierKey{}, "standard"),
+	)
+
+	if routes[0].Method() != http.MethodGet || routes[1].Path() != "/users" {
+		t.Errorf("unexpected routes %v %v", routes[0], routes[1])
+	}
+
+	// Metadata listed by Routes is a copy, so it can't change the registered routes.
+	router.Routes()[0].Meta[tierKey{}] = "premium"
+
+	for _, route := range router.Routes() {
+		if tier := route.Meta.Get(tierKey{}); tier != "standard" {
+			t.Errorf("expected %s metadata to be unchanged, got %v", route.Method, tier)
+		}
+	}
+}
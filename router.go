@@ -3,9 +3,24 @@ package httprouter
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
+var standardMethods = []string{
+	http.MethodGet,
+	http.MethodHead,
+	http.MethodPost,
+	http.MethodPut,
+	http.MethodPatch,
+	http.MethodDelete,
+	http.MethodConnect,
+	http.MethodOptions,
+	http.MethodTrace,
+}
+
 type HandlerFunc func(w http.ResponseWriter, req *http.Request) error
 
 type Middleware func(handler HandlerFunc) HandlerFunc
@@ -14,9 +29,17 @@ type ErrorHandler func(w http.ResponseWriter, req *http.Request, verbose bool, e
 
 type PanicHandler func(rw http.ResponseWriter, req *http.Request, verbose bool, pv interface{})
 
+// Router dispatches requests to the registered routes. Routes can be registered and removed
+// while serving requests, as lookups use a snapshot of the routes that's replaced on changes.
 type Router struct {
 	config config
-	root   *node
+	// The current *table, loaded by lookups without locking.
+	table atomic.Value
+
+	// Guards changes of the table along with names and errs.
+	mu    sync.Mutex
+	names map[string]*Route
+	errs  []error
 }
 
 // LookupResult contains information about a route lookup, which is returned from Lookup and
@@ -29,7 +52,17 @@ type LookupResult struct {
 	// will also be used in the case
 	Status  int
 	Handler HandlerFunc
-	Methods []string // Only has a value when StatusCode is MethodNotAllowed.
+	// Methods are the sorted methods of the matched path, only set when Status is
+	// http.StatusMethodNotAllowed or for OPTIONS requests. They're shared with other requests
+	// and must not be modified, but may be replaced before ServeLookupResult, which builds
+	// the Allow header from them.
+	Methods []string
+
+	// The methods Methods was copied from, with their precomputed Allow header.
+	allowed allowedMethods
+
+	// The pooled storage of RouteData.Params, released by ServeLookupResult.
+	params *Params
 }
 
 func New(opts ...Opt) *Router {
@@ -38,40 +71,370 @@ func New(opts ...Opt) *Router {
 		opt(&config)
 	}
 
-	return &Router{
-		root:   &node{path: "/"},
+	r := &Router{
 		config: config,
+		names:  make(map[string]*Route),
+	}
+	r.table.Store(&table{root: &node{path: "/"}})
+
+	if config.notFoundHandler != nil || config.methodNotAllowedHandler != nil {
+		r.addFallback(nil, "", r.chain(config.notFoundHandler, nil), r.chain(config.methodNotAllowedHandler, nil))
 	}
+
+	return r
 }
 
-// Handler registers HandlerFunc at given method and path
-func (r *Router) Handler(method, path string, handler HandlerFunc, middleware ...Middleware) {
-	switch {
-	case len(path) == 0:
-		panic("Path must be non empty")
-	case len(path) > 0 && path[0] != '/':
-		panic(fmt.Sprintf("Path %q must start with slash", path))
+// Handler registers HandlerFunc at given method and path.
+// It panics if the route is invalid or conflicts with an existing route,
+// unless the Router was created WithDeferredErrors.
+func (r *Router) Handler(method, path string, handler HandlerFunc, opts ...RouteOpt) *Route {
+	route, err := r.handle(nil, method, path, handler, opts)
+	r.check(err)
+
+	return route
+}
+
+// TryHandler registers HandlerFunc at given method and path like Handler, but returns
+// an error instead of panicking if the route is invalid or conflicts with an existing route.
+func (r *Router) TryHandler(method, path string, handler HandlerFunc, opts ...RouteOpt) (*Route, error) {
+	return r.handle(nil, method, path, handler, opts)
+}
+
+// HTTPHandler register http.Handler at given method and path
+func (r *Router) HTTPHandler(method, path string, handler http.Handler, opts ...RouteOpt) *Route {
+	return r.Handler(method, path, wrapHTTPHandler(handler), opts...)
+}
+
+// Handle registers HandlerFunc at given path for each of methods, sharing one middleware chain.
+// A Name option only applies to the route of the first method.
+func (r *Router) Handle(methods []string, path string, handler HandlerFunc, opts ...RouteOpt) []*Route {
+	routes, err := r.handleMethods(nil, methods, path, handler, opts)
+	r.check(err)
+
+	return routes
+}
+
+// Any registers HandlerFunc at given path for every standard method like Handle.
+func (r *Router) Any(path string, handler HandlerFunc, opts ...RouteOpt) []*Route {
+	return r.Handle(standardMethods, path, handler, opts...)
+}
+
+// Remove removes the route registered at given method and path, and reports whether it existed.
+// Like registering routes, it's safe while serving requests.
+func (r *Router) Remove(method, path string) bool {
+	return r.remove(nil, method, path)
+}
+
+// Group creates a Group of routes sharing given path prefix and middleware.
+func (r *Router) Group(prefix string, middleware ...Middleware) *Group {
+	root := Group{router: r}
+
+	return root.Group(prefix, middleware...)
+}
+
+// Mount registers handler for every standard method at prefix and any path below it.
+// The prefix is stripped from the request path before handler is called.
+func (r *Router) Mount(prefix string, handler http.Handler, middleware ...Middleware) {
+	r.check(r.mount(nil, prefix, handler, middleware))
+}
+
+// Build returns the errors collected while registering routes on a Router created
+// WithDeferredErrors as RegistrationErrors, or nil if all routes were registered.
+func (r *Router) Build() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.errs) == 0 {
+		return nil
+	}
+
+	errs := make(RegistrationErrors, len(r.errs))
+	copy(errs, r.errs)
+
+	return errs
+}
+
+func (r *Router) mount(h *host, prefix string, handler http.Handler, middleware []Middleware) error {
+	if err := validatePath(prefix); err != nil {
+		return err
+	}
+
+	base := strings.TrimSuffix(prefix, "/")
+	catchAll := base + "/*"
+	strip := strings.Count(base, "/")
+
+	stripped := func(rw http.ResponseWriter, req *http.Request) error {
+		handler.ServeHTTP(rw, stripPrefix(req, strip, r.config.rawPath))
+		return nil
+	}
+
+	c := newRouteConfig([]RouteOpt{Use(middleware...)})
+	routes := append(r.newRoutes(h, standardMethods, prefix, c), r.newRoutes(h, standardMethods, catchAll, c)...)
+	for _, route := range routes {
+		route.mount = prefix
+	}
+
+	// Both paths are registered at once, so a conflict of either leaves nothing behind.
+	return r.register(h, routes, r.chainRoute(stripped, c))
+}
+
+// handle registers handler configured with opts and returns its Route, which is also
+// returned alongside an error if registration failed.
+// The route is registered in the tree of h, or the default tree if h is nil.
+func (r *Router) handle(h *host, method, path string, handler HandlerFunc, opts []RouteOpt) (*Route, error) {
+	routes, err := r.handleMethods(h, []string{method}, path, handler, opts)
+
+	return routes[0], err
+}
+
+// handleMethods registers handler configured with opts for each of methods like handle,
+// wrapping it in middleware once for all of them.
+func (r *Router) handleMethods(h *host, methods []string, path string, handler HandlerFunc, opts []RouteOpt) ([]*Route, error) {
+	c := newRouteConfig(opts)
+	routes := r.newRoutes(h, methods, path, c)
+
+	if err := validatePath(path); err != nil {
+		return routes, err
+	}
+
+	if len(methods) == 0 {
+		return routes, &InvalidRouteError{Path: path, Reason: "route must have at least one method"}
+	}
+
+	if c.invalid != "" {
+		return routes, &InvalidRouteError{Path: path, Reason: c.invalid}
+	}
+
+	// Names are unique, so only the first route can be looked up by name.
+	if len(routes) != 0 {
+		routes[0].name = c.name
+	}
+
+	return routes, r.register(h, routes, r.chainRoute(handler, c))
+}
+
+// newRoutes creates the routes configured with c for each of methods at path in the tree of h.
+func (r *Router) newRoutes(h *host, methods []string, path string, c routeConfig) []*Route {
+	routes := make([]*Route, len(methods))
+	for i, method := range methods {
+		routes[i] = &Route{
+			router:     r,
+			host:       h,
+			method:     method,
+			path:       path,
+			middleware: c.middlewareCount(len(r.config.middleware)),
+			meta:       c.meta.clone(),
+			summary:    c.summary,
+			timeout:    c.timeout,
+		}
 	}
 
-	middleware = append(r.config.middleware, middleware...)
+	return routes
+}
+
+// chainRoute wraps handler in the middleware of c, preceded by the global middleware.
+func (r *Router) chainRoute(handler HandlerFunc, c routeConfig) HandlerFunc {
+	middleware := c.middleware
+	if c.timeout > 0 {
+		middleware = joinMiddleware([]Middleware{timeoutMiddleware(c.timeout)}, middleware)
+	}
 
-	if len(middleware) > 0 {
-		for i := len(middleware) - 1; i != 0; i-- {
-			handler = middleware[i](handler)
+	return r.chain(handler, middleware)
+}
+
+// register adds routes served by handler to the tree of h along with their names in a
+// single update, so none of them is registered if any fails.
+func (r *Router) register(h *host, routes []*Route, handler HandlerFunc) error {
+	var head HandlerFunc
+	if r.config.implicitHead {
+		head = headHandler(handler)
+	}
+
+	return r.update(func(t *table) error {
+		// The names are guarded by r.mu, which is held during updates.
+		for _, route := range routes {
+			if existing, ok := r.names[route.name]; ok && route.name != "" {
+				return &DuplicateNameError{Name: route.name, Path: route.path, Existing: existing.path}
+			}
 		}
+
+		root := t.mutableRoot(h)
+		for _, route := range routes {
+			var implicitHead HandlerFunc
+			if route.method == http.MethodGet {
+				implicitHead = head
+			}
+
+			if err := root.registerPath(route, handler, implicitHead, r.config.redirectTrailingSlash); err != nil {
+				return err
+			}
+		}
+
+		for _, route := range routes {
+			if route.name != "" {
+				r.names[route.name] = route
+			}
+		}
+
+		return nil
+	})
+}
+
+// remove removes the route registered at method and path in the tree of h,
+// or the default tree if h is nil.
+func (r *Router) remove(h *host, method, path string) bool {
+	if len(path) == 0 || path[0] != '/' {
+		return false
+	}
+
+	trimmed := path
+	if len(path) > 1 && path[len(path)-1] == '/' && r.config.redirectTrailingSlash {
+		trimmed = path[:len(path)-1]
 	}
 
-	r.root.registerPath(method, path, handler, r.config.redirectTrailingSlash)
+	var removed *Route
+	_ = r.update(func(t *table) error {
+		n, parent := t.mutableRoot(h).findPath(trimmed[1:], false)
+		if n == nil || n.leafRoute[method] == nil || n.leafRoute[method].path != path {
+			return errUnchanged
+		}
+
+		if method == http.MethodHead && n.implicitHead {
+			// HEAD is served through GET, which has to be removed instead.
+			return errUnchanged
+		}
+
+		removed = n.leafRoute[method]
+		n.removeHandler(method)
+
+		if get := n.leafHandler[http.MethodGet]; get != nil && method == http.MethodHead && r.config.implicitHead {
+			// Serve HEAD through GET again after the explicit handler is removed.
+			_ = n.setHandler(http.MethodHead, headHandler(get), n.leafRoute[http.MethodGet], true)
+		}
+
+		if len(n.leafHandler) == 0 && n.isCatchAll {
+			// Let catch-alls with other names be registered at the parent.
+			parent.catchAllChild = nil
+		}
+
+		return nil
+	})
+
+	if removed == nil {
+		return false
+	}
+
+	r.mu.Lock()
+	if removed.name != "" && removed.method == method && r.names[removed.name] == removed {
+		delete(r.names, removed.name)
+	}
+	r.mu.Unlock()
+
+	return true
 }
 
-// HTTPHandler register http.Handler at given method and path
-func (r *Router) HTTPHandler(method, path string, handler http.Handler, middleware ...Middleware) {
-	h := func(rw http.ResponseWriter, r *http.Request) error {
-		handler.ServeHTTP(rw, r)
+// chain wraps handler in the global middleware followed by middleware.
+// A nil handler is returned as is.
+func (r *Router) chain(handler HandlerFunc, middleware []Middleware) HandlerFunc {
+	if handler == nil {
 		return nil
 	}
 
-	r.Handler(method, path, h, middleware...)
+	middleware = joinMiddleware(r.config.middleware, middleware)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	return handler
+}
+
+// check panics with err, or collects it to be returned from Build if the Router
+// was created WithDeferredErrors.
+func (r *Router) check(err error) {
+	if err == nil {
+		return
+	}
+
+	if r.config.deferErrors {
+		r.mu.Lock()
+		r.errs = append(r.errs, err)
+		r.mu.Unlock()
+
+		return
+	}
+
+	panic(err)
+}
+
+// stripPrefix returns a shallow copy of req with the first n segments removed from its path.
+// The segments are counted on the escaped path if rawPath is set, so encoded slashes
+// don't split them.
+func stripPrefix(req *http.Request, n int, rawPath bool) *http.Request {
+	u := *req.URL
+	if escaped := stripSegments(u.EscapedPath(), n); rawPath {
+		if unescaped, err := url.PathUnescape(escaped); err == nil {
+			u.Path = unescaped
+			u.RawPath = ""
+			if escaped != unescaped {
+				u.RawPath = escaped
+			}
+		} else {
+			u.Path = stripSegments(u.Path, n)
+			u.RawPath = ""
+		}
+	} else {
+		u.Path = stripSegments(u.Path, n)
+		u.RawPath = stripSegments(u.RawPath, n)
+
+		// Encoded slashes in the stripped segments make RawPath disagree with Path,
+		// in which case it's dropped and re-derived from Path when needed.
+		if unescaped, err := url.PathUnescape(u.RawPath); err != nil || unescaped != u.Path {
+			u.RawPath = ""
+		}
+	}
+
+	r2 := new(http.Request)
+	*r2 = *req
+	r2.URL = &u
+
+	return r2
+}
+
+func stripSegments(path string, n int) string {
+	if path == "" {
+		return ""
+	}
+
+	for ; n > 0; n-- {
+		next := strings.IndexByte(path[1:], '/')
+		if next < 0 {
+			return "/"
+		}
+		path = path[next+1:]
+	}
+
+	return path
+}
+
+// headHandler serves HEAD requests with handler, discarding the response body.
+func headHandler(handler HandlerFunc) HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) error {
+		w := newHeadResponseWriter(rw)
+		if err := handler(w, req); err != nil {
+			return err
+		}
+
+		w.finish()
+
+		return nil
+	}
+}
+
+func wrapHTTPHandler(handler http.Handler) HandlerFunc {
+	return func(rw http.ResponseWriter, req *http.Request) error {
+		handler.ServeHTTP(rw, req)
+		return nil
+	}
 }
 
 // ServeHTTP implements http.Handler
@@ -81,68 +444,118 @@ func (r *Router) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 }
 
 func (r *Router) Lookup(rw http.ResponseWriter, req *http.Request) LookupResult {
+	return r.lookup(r.load(), req)
+}
+
+// lookup looks up the route for req in the routes of t.
+func (r *Router) lookup(t *table, req *http.Request) LookupResult {
 	path := req.URL.Path
+	if r.config.rawPath {
+		path = req.URL.EscapedPath()
+	}
 
+	hostname := hostname(req.Host)
+
+	if r.config.redirectCleanPath && !isCleanPath(path) {
+		if clean := cleanPath(path); r.matches(t, req.Method, hostname, clean) {
+			return r.redirect(req, clean)
+		}
+	}
+
+	requestPath := path
 	trailingSlash := strings.HasSuffix(path, "/") && len(path) > 1
 	if trailingSlash && r.config.redirectTrailingSlash {
 		path = path[:len(path)-1]
 	}
 
-	n, handler, params := r.root.search(req.Method, path[1:])
+	params := getParams()
+	n, handler, pathEnd := t.search(req.Method, hostname, path, params, r.config.rawPath)
+
 	if n == nil {
+		putParams(params)
+
+		if r.config.redirectCaseInsensitive {
+			if fixed, ok := r.searchCaseInsensitive(t, hostname, path, trailingSlash); ok && fixed != requestPath {
+				return r.redirect(req, fixed)
+			}
+		}
+
 		return LookupResult{
-			Status: http.StatusNotFound,
+			Status:  http.StatusNotFound,
+			Handler: t.notFound(hostname, path),
 		}
 	}
 
-	var paramsMap map[string]string
-	if len(params) != 0 {
-		if len(params) != len(n.leafWildcardNames) {
+	pathParams := (*params)[pathEnd:]
+	if len(pathParams) != 0 {
+		if len(pathParams) != len(n.leafWildcardNames) {
 			panic(fmt.Sprintf("httprouter parameter list length mismatch: %v, %v",
-				params, n.leafWildcardNames))
+				pathParams, n.leafWildcardNames))
 		}
 
-		paramsMap = make(map[string]string)
-		numParams := len(params)
+		for i, j := 0, len(pathParams)-1; i < j; i, j = i+1, j-1 {
+			pathParams[i], pathParams[j] = pathParams[j], pathParams[i]
+		}
 
-		for index := 0; index < numParams; index++ {
-			name := n.leafWildcardNames[numParams-index-1]
+		for i, name := range n.leafWildcardNames {
 			if len(name) == 0 {
 				name = "*"
 			}
 
-			paramsMap[name] = params[index]
+			// Values are only escaped when routing on the escaped path.
+			value := pathParams[i].Value
+			if r.config.rawPath {
+				if unescaped, err := url.PathUnescape(value); err == nil {
+					value = unescaped
+				}
+			}
+
+			pathParams[i] = Parameter{Key: name, Value: value}
 		}
 	}
 
+	if len(*params) == 0 {
+		putParams(params)
+		params = nil
+	}
+
 	routeData := RouteData{
-		Route:  n.route,
-		Params: paramsMap,
+		Route: n.route,
 	}
 
-	if handler == nil {
-		var methods []string
-		for method := range n.leafHandler {
-			methods = append(methods, method)
-		}
+	if params != nil {
+		routeData.Params = *params
+	}
 
-		if _, ok := n.leafHandler[http.MethodOptions]; !ok && r.config.handleOptions {
-			methods = append(methods, "OPTIONS")
+	if route := n.leafRoute[req.Method]; route != nil {
+		routeData.Mount = route.mount
+		routeData.Meta = route.meta
+	}
+
+	if handler == nil {
+		allowed := n.allowed
+		if r.config.handleOptions {
+			allowed = n.allowedWithOptions
 		}
 
 		if req.Method == "OPTIONS" && r.config.optionsHandler != nil {
 			return LookupResult{
 				Status:    http.StatusOK,
 				RouteData: routeData,
+				params:    params,
 				Handler:   r.config.optionsHandler,
-				Methods:   methods,
+				Methods:   allowed.methods,
+				allowed:   allowed,
 			}
 		}
 
 		return LookupResult{
 			Status:    http.StatusMethodNotAllowed,
 			RouteData: routeData,
-			Methods:   methods,
+			params:    params,
+			Handler:   t.methodNotAllowed(hostname, path),
+			Methods:   allowed.methods,
+			allowed:   allowed,
 		}
 	}
 
@@ -160,12 +573,10 @@ func (r *Router) Lookup(rw http.ResponseWriter, req *http.Request) LookupResult
 		}
 
 		return LookupResult{
-			Status: status,
-			Handler: func(rw http.ResponseWriter, req *http.Request) error {
-				http.Redirect(rw, req, path, status)
-				return nil
-			},
+			Status:    status,
+			Handler:   r.redirectHandler(req, path, status),
 			RouteData: routeData,
+			params:    params,
 		}
 	}
 
@@ -173,10 +584,146 @@ func (r *Router) Lookup(rw http.ResponseWriter, req *http.Request) LookupResult
 		Status:    http.StatusOK,
 		Handler:   handler,
 		RouteData: routeData,
+		params:    params,
 	}
 }
 
+// search looks up path in the trees of the hosts matching hostname, then in the default tree.
+// A node of a host tree without handler for method is only returned if the default tree
+// has no handler for it either. Host parameters are appended to params, followed by the
+// path parameters from pathEnd on. The path is escaped if rawPath is set.
+func (t *table) search(method, hostname, path string, params *Params, rawPath bool) (n *node, handler HandlerFunc, pathEnd int) {
+	var candidate *node
+	for _, ht := range t.hosts {
+		if !ht.host.match(hostname, params) {
+			continue
+		}
+
+		pathEnd = len(*params)
+		if n, handler = searchTree(ht.root, ht.compiled, method, path[1:], params, rawPath); handler != nil {
+			return n, handler, pathEnd
+		}
+
+		if n != nil {
+			// Keep the parameters of the candidate past the ones of the default tree.
+			candidate = n
+			break
+		}
+
+		*params = (*params)[:0]
+	}
+
+	base := len(*params)
+	n, handler = searchTree(t.root, t.compiled, method, path[1:], params, rawPath)
+	if candidate == nil {
+		return n, handler, 0
+	}
+
+	if handler == nil {
+		*params = (*params)[:base]
+		return candidate, nil, pathEnd
+	}
+
+	*params = (*params)[:copy(*params, (*params)[base:])]
+
+	return n, handler, 0
+}
+
+// matches reports whether path matches a route, for any method.
+func (r *Router) matches(t *table, method, hostname, path string) bool {
+	if len(path) > 1 && strings.HasSuffix(path, "/") && r.config.redirectTrailingSlash {
+		path = path[:len(path)-1]
+	}
+
+	params := getParams()
+	defer putParams(params)
+
+	n, _, _ := t.search(method, hostname, path, params, r.config.rawPath)
+
+	return n != nil
+}
+
+// searchCaseInsensitive looks up path ignoring the case of static segments, returning the
+// path of the matched route with its case.
+func (r *Router) searchCaseInsensitive(t *table, hostname, path string, trailingSlash bool) (string, bool) {
+	fixed := make([]byte, 0, len(path)+1)
+	fixed = append(fixed, '/')
+
+	var scratch Params
+	for _, ht := range t.hosts {
+		ok := ht.host.match(hostname, &scratch)
+		scratch = scratch[:0]
+		if !ok {
+			continue
+		}
+
+		if n, out := ht.root.searchCaseInsensitive(path[1:], fixed, r.config.rawPath); n != nil {
+			return r.fixTrailingSlash(n, string(out), trailingSlash), true
+		}
+	}
+
+	if n, out := t.root.searchCaseInsensitive(path[1:], fixed, r.config.rawPath); n != nil {
+		return r.fixTrailingSlash(n, string(out), trailingSlash), true
+	}
+
+	return "", false
+}
+
+func (r *Router) fixTrailingSlash(n *node, path string, trailingSlash bool) string {
+	switch {
+	case n.isCatchAll:
+		return path
+	case r.config.redirectTrailingSlash && n.addSlash,
+		!r.config.redirectTrailingSlash && trailingSlash && !strings.HasSuffix(path, "/"):
+		return path + "/"
+	default:
+		return path
+	}
+}
+
+// redirect returns a result redirecting req to path, with 301 for GET and 308 for other
+// methods so the body is preserved.
+func (r *Router) redirect(req *http.Request, path string) LookupResult {
+	status := http.StatusPermanentRedirect
+	if req.Method == http.MethodGet {
+		status = http.StatusMovedPermanently
+	}
+
+	return LookupResult{
+		Status:  status,
+		Handler: r.redirectHandler(req, path, status),
+	}
+}
+
+// redirectHandler redirects to path, keeping the query of req. The path is escaped
+// unless the router routes on escaped paths already.
+func (r *Router) redirectHandler(req *http.Request, path string, status int) HandlerFunc {
+	u := url.URL{Path: path, RawQuery: req.URL.RawQuery}
+	if r.config.rawPath {
+		u.RawPath = path
+		if unescaped, err := url.PathUnescape(path); err == nil {
+			u.Path = unescaped
+		}
+	}
+
+	location := u.String()
+
+	return func(rw http.ResponseWriter, req *http.Request) error {
+		http.Redirect(rw, req, location, status)
+		return nil
+	}
+}
+
+// Release returns the storage of the route parameters to a pool for reuse by subsequent
+// lookups. It's called by ServeLookupResult, so it only needs to be called for results
+// that are not served, after which RouteData.Params must no longer be used.
+func (lr LookupResult) Release() {
+	putParams(lr.params)
+}
+
 func (r *Router) ServeLookupResult(rw http.ResponseWriter, req *http.Request, lr LookupResult) {
+	defer lr.Release()
+
 	w := NewResponseWriter(rw)
 	ctx := WithRouteData(req.Context(), lr.RouteData)
 	req = req.WithContext(ctx)
@@ -193,21 +740,41 @@ func (r *Router) ServeLookupResult(rw http.ResponseWriter, req *http.Request, lr
 		defer r.config.logRoundtrip(w, req)
 	}
 
+	if len(lr.Methods) != 0 {
+		w.Header().Set("Allow", lr.allowHeader())
+	}
+
 	if lr.Handler == nil {
 		r.config.errorHandler(w, req, r.config.verbose, NewError(lr.Status, Operational()))
 		return
 	}
 
-	if len(lr.Methods) != 0 {
-		w.Header().Set("Allow", strings.Join(lr.Methods, ", "))
-	}
-
 	err := lr.Handler(w, req)
 	if err != nil {
 		r.config.errorHandler(w, req, r.config.verbose, AsError(err))
 	}
 }
 
+// allowHeader returns the Allow header for Methods, which is precomputed unless
+// the caller replaced them.
+func (lr *LookupResult) allowHeader() string {
+	if len(lr.Methods) == len(lr.allowed.methods) {
+		same := true
+		for i, method := range lr.Methods {
+			if method != lr.allowed.methods[i] {
+				same = false
+				break
+			}
+		}
+
+		if same {
+			return lr.allowed.header
+		}
+	}
+
+	return strings.Join(lr.Methods, ", ")
+}
+
 func (r *Router) DumpTree() string {
-	return r.root.dumpTree("", "")
+	return r.load().root.dumpTree("", "")
 }
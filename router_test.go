@@ -8,6 +8,7 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -211,7 +212,7 @@ func TestRouteData(t *testing.T) {
 		method         string
 		route          string
 		path           string
-		expectedParams map[string]string
+		expectedParams httprouter.Params
 	}{
 		{
 			name:   "static route",
@@ -230,8 +231,8 @@ func TestRouteData(t *testing.T) {
 			method: http.MethodGet,
 			path:   "/user/1",
 			route:  "/user/:id",
-			expectedParams: map[string]string{
-				"id": "1",
+			expectedParams: httprouter.Params{
+				{Key: "id", Value: "1"},
 			},
 		},
 		{
@@ -245,9 +246,9 @@ func TestRouteData(t *testing.T) {
 			method: http.MethodGet,
 			path:   "/user/1/address/2",
 			route:  "/user/:user_id/address/:address_id",
-			expectedParams: map[string]string{
-				"user_id":    "1",
-				"address_id": "2",
+			expectedParams: httprouter.Params{
+				{Key: "user_id", Value: "1"},
+				{Key: "address_id", Value: "2"},
 			},
 		},
 		{
@@ -255,8 +256,8 @@ func TestRouteData(t *testing.T) {
 			method: http.MethodGet,
 			path:   "/user/special/address/1",
 			route:  "/user/special/address/:address_id",
-			expectedParams: map[string]string{
-				"address_id": "1",
+			expectedParams: httprouter.Params{
+				{Key: "address_id", Value: "1"},
 			},
 		},
 		{
@@ -264,8 +265,8 @@ func TestRouteData(t *testing.T) {
 			method: http.MethodGet,
 			path:   "/static/foo.txt",
 			route:  "/static/*",
-			expectedParams: map[string]string{
-				"*": "foo.txt",
+			expectedParams: httprouter.Params{
+				{Key: "*", Value: "foo.txt"},
 			},
 		},
 	}
@@ -318,6 +319,7 @@ type routeDataObserver struct {
 }
 
 func (o *routeDataObserver) add(entry httprouter.RouteData) {
+	entry.Params = entry.Params.Copy()
 	o.entries = append(o.entries, entry)
 }
 
@@ -328,3 +330,484 @@ func (o *routeDataObserver) takeAll() []httprouter.RouteData {
 
 	return dst
 }
+
+func TestRouteDataFallback(t *testing.T) {
+	router := httprouter.New()
+
+	var params httprouter.Params
+	handler := func(w http.ResponseWriter, req *http.Request) error {
+		params = httprouter.GetRouteData(req.Context()).Params.Copy()
+		return nil
+	}
+
+	router.Handler(http.MethodPost, "/a/b/:y", handler)
+	router.Handler(http.MethodGet, "/a/:x/c", handler)
+	router.Handler(http.MethodGet, "/a/*rest", handler)
+
+	tests := []struct {
+		name           string
+		path           string
+		expectedParams httprouter.Params
+	}{
+		{
+			name:           "fall back from static to wildcard",
+			path:           "/a/b/c",
+			expectedParams: httprouter.Params{{Key: "x", Value: "b"}},
+		},
+		{
+			name:           "fall back from static to catch-all",
+			path:           "/a/b/d",
+			expectedParams: httprouter.Params{{Key: "rest", Value: "b/d"}},
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("expected status code %d, got %d", http.StatusOK, rec.Code)
+			}
+
+			if diff := cmp.Diff(test.expectedParams, params); diff != "" {
+				t.Error("unexpected params", diff)
+			}
+		})
+	}
+}
+
+func TestRouterImplicitHead(t *testing.T) {
+	router := httprouter.New(httprouter.WithImplicitHead(true))
+
+	var size int
+	router.Handler(http.MethodGet, "/hello", func(w http.ResponseWriter, req *http.Request) error {
+		_, err := io.WriteString(w, "Hello World!")
+		size = w.(httprouter.ResponseWriter).Size()
+
+		return err
+	})
+
+	router.Handler(http.MethodGet, "/explicit", httprouter.NoopHandler)
+	router.Handler(http.MethodHead, "/explicit", func(w http.ResponseWriter, req *http.Request) error {
+		w.Header().Set("X-Explicit", "true")
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	})
+
+	router.Handler(http.MethodHead, "/explicit-first", func(w http.ResponseWriter, req *http.Request) error {
+		w.Header().Set("X-Explicit", "true")
+		return nil
+	})
+	router.Handler(http.MethodGet, "/explicit-first", httprouter.NoopHandler)
+
+	t.Run("implicit head", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/hello", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status code %d, got %d", http.StatusOK, rec.Code)
+		}
+
+		if rec.Body.Len() != 0 {
+			t.Errorf("expected no body, got %q", rec.Body.String())
+		}
+
+		if contentLength := rec.Header().Get("Content-Length"); contentLength != "12" {
+			t.Errorf("expected Content-Length 12, got %q", contentLength)
+		}
+
+		if size != 12 {
+			t.Errorf("expected size 12, got %d", size)
+		}
+	})
+
+	for _, path := range []string{"/explicit", "/explicit-first"} {
+		path := path
+		t.Run("explicit head "+path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, path, nil))
+
+			if rec.Header().Get("X-Explicit") != "true" {
+				t.Error("expected explicit head handler")
+			}
+		})
+	}
+
+	routes := router.Routes()
+	if len(routes) != 5 {
+		t.Errorf("expected implicit head routes not to be listed, got %+v", routes)
+	}
+}
+
+func TestRouterRedirectPath(t *testing.T) {
+	router := httprouter.New(
+		httprouter.WithRedirectCleanPath(true),
+		httprouter.WithRedirectCaseInsensitive(true),
+	)
+
+	router.Handler(http.MethodGet, "/users/:id", httprouter.NoopHandler)
+	router.Handler(http.MethodPost, "/users/:id/Avatar", httprouter.NoopHandler)
+	router.Handler(http.MethodGet, "/docs/", httprouter.NoopHandler)
+	router.Handler(http.MethodGet, "/files/*path", httprouter.NoopHandler)
+
+	tests := []struct {
+		name             string
+		method           string
+		path             string
+		expectedStatus   int
+		expectedLocation string
+	}{
+		{
+			name:             "duplicate slashes",
+			method:           http.MethodGet,
+			path:             "//users///1?expand=true",
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "/users/1?expand=true",
+		},
+		{
+			name:             "dot segments",
+			method:           http.MethodGet,
+			path:             "/docs/./../users/1",
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "/users/1",
+		},
+		{
+			name:             "clean path keeps trailing slash",
+			method:           http.MethodGet,
+			path:             "/files/../docs//",
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "/docs/",
+		},
+		{
+			name:           "unmatched clean path",
+			method:         http.MethodGet,
+			path:           "/users/1/../../missing",
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:             "case insensitive",
+			method:           http.MethodGet,
+			path:             "/USERS/Ab",
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "/users/Ab",
+		},
+		{
+			name:             "case insensitive post",
+			method:           http.MethodPost,
+			path:             "/Users/1/avatar",
+			expectedStatus:   http.StatusPermanentRedirect,
+			expectedLocation: "/users/1/Avatar",
+		},
+		{
+			name:             "case insensitive adds trailing slash",
+			method:           http.MethodGet,
+			path:             "/DOCS",
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "/docs/",
+		},
+		{
+			name:             "case insensitive catch-all",
+			method:           http.MethodGet,
+			path:             "/Files/A/b",
+			expectedStatus:   http.StatusMovedPermanently,
+			expectedLocation: "/files/A/b",
+		},
+		{
+			name:           "exact match",
+			method:         http.MethodGet,
+			path:           "/users/1",
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(test.method, test.path, nil))
+
+			if rec.Code != test.expectedStatus {
+				t.Fatalf("expected status code %d, got %d", test.expectedStatus, rec.Code)
+			}
+
+			if location := rec.Header().Get("Location"); location != test.expectedLocation {
+				t.Errorf("expected location %q, got %q", test.expectedLocation, location)
+			}
+		})
+	}
+}
+
+func TestRouterRawPath(t *testing.T) {
+	handler := func(w http.ResponseWriter, req *http.Request) error {
+		params := httprouter.GetRouteData(req.Context()).Params
+		_, err := fmt.Fprintf(w, "%s|%s", params.Get("bucket"), params.Get("key"))
+		return err
+	}
+
+	tests := []struct {
+		name             string
+		rawPath          bool
+		path             string
+		expectedCode     int
+		expectedBody     string
+		expectedLocation string
+	}{
+		{
+			name:         "encoded slash",
+			rawPath:      true,
+			path:         "/buckets/b%2F1/objects/a%2Fb+c",
+			expectedCode: http.StatusOK,
+			expectedBody: "b/1|a/b+c",
+		},
+		{
+			name:         "catch-all",
+			rawPath:      true,
+			path:         "/buckets/b/objects/dir%2Fa/b%20c",
+			expectedCode: http.StatusOK,
+			expectedBody: "b|dir/a/b c",
+		},
+		{
+			name:         "decoded once",
+			rawPath:      true,
+			path:         "/buckets/b/objects/a%2520b",
+			expectedCode: http.StatusOK,
+			expectedBody: "b|a%20b",
+		},
+		{
+			name:         "constraint on unescaped value",
+			rawPath:      true,
+			path:         "/names/a%20b",
+			expectedCode: http.StatusOK,
+			expectedBody: "a b|",
+		},
+		{
+			name:             "case-insensitive redirect with constraint",
+			rawPath:          true,
+			path:             "/NAMES/a%20b",
+			expectedCode:     http.StatusMovedPermanently,
+			expectedLocation: "/names/a%20b",
+		},
+		{
+			name:         "encoded slash splits segments",
+			path:         "/buckets/b%2F1/objects/a",
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name:         "decoded path",
+			path:         "/buckets/b/objects/a%2520b+c",
+			expectedCode: http.StatusOK,
+			expectedBody: "b|a%20b+c",
+		},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			router := httprouter.New(
+				httprouter.WithRawPath(test.rawPath),
+				httprouter.WithRedirectCaseInsensitive(true),
+			)
+			router.Handler(http.MethodGet, "/buckets/:bucket/objects/*key", handler)
+			router.Handler(http.MethodGet, "/names/:bucket<[a-z ]+>", handler)
+
+			for name, h := range map[string]http.Handler{"router": router, "compiled": router.Compile()} {
+				rec := httptest.NewRecorder()
+				h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+				if rec.Code != test.expectedCode {
+					t.Fatalf("%s: expected status code %d, got %d", name, test.expectedCode, rec.Code)
+				}
+
+				if test.expectedBody != "" && rec.Body.String() != test.expectedBody {
+					t.Errorf("%s: expected body %q, got %q", name, test.expectedBody, rec.Body.String())
+				}
+
+				if location := rec.Header().Get("Location"); location != test.expectedLocation {
+					t.Errorf("%s: expected location %q, got %q", name, test.expectedLocation, location)
+				}
+			}
+		})
+	}
+}
+
+func TestRouterRemove(t *testing.T) {
+	router := httprouter.New(httprouter.WithImplicitHead(true))
+	router.GET("/users/:id", httprouter.NoopHandler, httprouter.Name("user"))
+	router.DELETE("/users/:id", httprouter.NoopHandler)
+	router.GET("/files/*path", httprouter.NoopHandler)
+	router.GET("/docs/", httprouter.NoopHandler)
+	router.Host("api.example.com").GET("/status", httprouter.NoopHandler)
+
+	serve := func(method, path string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.Host = "api.example.com"
+		router.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	if router.Remove(http.MethodGet, "/users/:name") {
+		t.Error("expected route with other parameter name not to be removed")
+	}
+
+	if router.Remove(http.MethodHead, "/users/:id") {
+		t.Error("expected implicit head not to be removed")
+	}
+
+	if code := serve(http.MethodHead, "/users/1"); code != http.StatusOK {
+		t.Errorf("expected implicit head to be served, got status code %d", code)
+	}
+
+	if _, err := router.URL("user", map[string]string{"id": "1"}); err != nil {
+		t.Error("expected name of GET route to be kept", err)
+	}
+
+	if !router.Remove(http.MethodGet, "/users/:id") {
+		t.Fatal("expected route to be removed")
+	}
+
+	if router.Remove(http.MethodGet, "/users/:id") {
+		t.Error("expected route to be removed only once")
+	}
+
+	if code := serve(http.MethodGet, "/users/1"); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected status code %d, got %d", http.StatusMethodNotAllowed, code)
+	}
+
+	if code := serve(http.MethodHead, "/users/1"); code != http.StatusMethodNotAllowed {
+		t.Errorf("expected implicit head to be removed, got status code %d", code)
+	}
+
+	if _, err := router.URL("user", map[string]string{"id": "1"}); err == nil {
+		t.Error("expected name of removed route to be released")
+	}
+
+	router.Remove(http.MethodDelete, "/users/:id")
+	router.GET("/users/:name", httprouter.NoopHandler)
+
+	if code := serve(http.MethodGet, "/users/1"); code != http.StatusOK {
+		t.Errorf("expected route with new parameter name to be served, got status code %d", code)
+	}
+
+	router.Remove(http.MethodGet, "/files/*path")
+	router.GET("/files/*name", httprouter.NoopHandler)
+
+	if code := serve(http.MethodGet, "/files/a/b"); code != http.StatusOK {
+		t.Errorf("expected catch-all with new name to be served, got status code %d", code)
+	}
+
+	if !router.Remove(http.MethodGet, "/docs/") {
+		t.Error("expected route with trailing slash to be removed")
+	}
+
+	if !router.Host("api.example.com").Remove(http.MethodGet, "/status") {
+		t.Error("expected host route to be removed")
+	}
+
+	var routes []string
+	for _, route := range router.Routes() {
+		routes = append(routes, route.Method+" "+route.Route)
+	}
+
+	expected := []string{"GET /files/*name", "GET /users/:name"}
+	if diff := cmp.Diff(expected, routes); diff != "" {
+		t.Error("unexpected routes", diff)
+	}
+}
+
+func TestRouterConcurrentRegistration(t *testing.T) {
+	router := httprouter.New()
+	router.GET("/static", httprouter.NoopHandler)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		i := i
+
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				path := fmt.Sprintf("/plugins/%d/%d/:id", i, j)
+				router.GET(path, httprouter.NoopHandler)
+				router.Remove(http.MethodGet, path)
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/static", nil))
+
+				if rec.Code != http.StatusOK {
+					t.Errorf("expected status code %d, got %d", http.StatusOK, rec.Code)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if routes := router.Routes(); len(routes) != 1 {
+		t.Errorf("expected only the static route to remain, got %+v", routes)
+	}
+}
+
+func TestRouterAllowHeader(t *testing.T) {
+	router := httprouter.New(httprouter.WithImplicitHead(true))
+	router.PUT("/users/:id", httprouter.NoopHandler)
+	router.DELETE("/users/:id", httprouter.NoopHandler)
+	router.GET("/users/:id", httprouter.NoopHandler)
+	router.PATCH("/users/:id", httprouter.NoopHandler)
+
+	for _, method := range []string{http.MethodPost, http.MethodOptions} {
+		method := method
+		t.Run(method, func(t *testing.T) {
+			req := httptest.NewRequest(method, "/users/1", nil)
+
+			for i := 0; i < 10; i++ {
+				rec := httptest.NewRecorder()
+				router.ServeHTTP(rec, req)
+
+				if allow := rec.Header().Get("Allow"); allow != "DELETE, GET, HEAD, OPTIONS, PATCH, PUT" {
+					t.Fatalf("unexpected Allow header %q", allow)
+				}
+			}
+
+			allocs := testing.AllocsPerRun(100, func() {
+				lr := router.Lookup(nil, req)
+				lr.Release()
+			})
+
+			if allocs != 0 {
+				t.Errorf("expected no allocations, got %v", allocs)
+			}
+		})
+	}
+
+	lr := router.Lookup(nil, httptest.NewRequest(http.MethodPost, "/users/1", nil))
+	lr.Methods = []string{http.MethodGet, http.MethodHead}
+
+	rec := httptest.NewRecorder()
+	router.ServeLookupResult(rec, httptest.NewRequest(http.MethodPost, "/users/1", nil), lr)
+
+	if allow := rec.Header().Get("Allow"); allow != "GET, HEAD" {
+		t.Errorf("expected Allow header of replaced methods, got %q", allow)
+	}
+
+	router.Remove(http.MethodPatch, "/users/:id")
+
+	lr = router.Lookup(nil, httptest.NewRequest(http.MethodPost, "/users/1", nil))
+	defer lr.Release()
+
+	expected := []string{"DELETE", "GET", "HEAD", "OPTIONS", "PUT"}
+	if diff := cmp.Diff(expected, lr.Methods); diff != "" {
+		t.Error("unexpected methods after removing route", diff)
+	}
+}
@@ -0,0 +1,84 @@
+package httprouter
+
+import "errors"
+
+// errUnchanged is returned from update functions to keep the route table as is.
+var errUnchanged = errors.New("route table unchanged")
+
+// table holds the route trees and fallbacks of a Router. Tables are never modified once
+// stored in the Router, so lookups can use them without locking while routes are changed
+// on a copy.
+type table struct {
+	root *node
+	// The compiled form of root, if the table was compiled.
+	compiled *cnode
+	// Host trees with static hosts before wildcard ones.
+	hosts []hostTree
+	// Fallbacks ordered from most to least specific.
+	fallbacks []*fallback
+}
+
+type hostTree struct {
+	host     *host
+	root     *node
+	compiled *cnode
+}
+
+// clone returns a copy of t sharing its trees and fallbacks, which must be replaced
+// rather than modified.
+func (t *table) clone() *table {
+	return &table{
+		root:      t.root,
+		hosts:     append([]hostTree(nil), t.hosts...),
+		fallbacks: append([]*fallback(nil), t.fallbacks...),
+	}
+}
+
+// mutableRoot replaces the root of the tree of h, or the default tree if h is nil,
+// with a clone and returns it.
+func (t *table) mutableRoot(h *host) *node {
+	if h == nil {
+		t.root = t.root.clone()
+		return t.root
+	}
+
+	for i := range t.hosts {
+		if t.hosts[i].host == h {
+			t.hosts[i].root = t.hosts[i].root.clone()
+			return t.hosts[i].root
+		}
+	}
+
+	panic("httprouter host not found in route table. Please report this as a bug.")
+}
+
+// searchTree searches the compiled tree if there is one, or the tree below root otherwise.
+func searchTree(root *node, compiled *cnode, method, path string, params *Params, rawPath bool) (*node, HandlerFunc) {
+	if compiled != nil {
+		return compiled.search(method, path, params, rawPath)
+	}
+
+	return root.search(method, path, params, rawPath)
+}
+
+// load returns the current route table.
+func (r *Router) load() *table {
+	return r.table.Load().(*table)
+}
+
+// update applies fn to a copy of the current route table, which replaces it unless fn
+// returns an error. Updates are serialized, while lookups keep using the previous table
+// until it's replaced.
+func (r *Router) update(fn func(t *table) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t := r.load().clone()
+	if err := fn(t); err != nil {
+		return err
+	}
+
+	r.table.Store(t)
+
+	return nil
+}
@@ -2,7 +2,8 @@ package httprouter
 
 import (
 	"fmt"
-	"net/url"
+	"net/http"
+	"sort"
 	"strings"
 )
 
@@ -16,8 +17,9 @@ type node struct {
 	staticIndices []byte
 	staticChild   []*node
 
-	// If none of the above match, check the wildcard children
-	wildcardChild *node
+	// If none of the above match, check the wildcard children. Children with a
+	// constraint come first in registration order, followed by the unconstrained one.
+	wildcardChildren []*node
 
 	// If none of the above match, then we use the catch-all, if applicable.
 	catchAllChild *node
@@ -26,13 +28,73 @@ type node struct {
 
 	addSlash   bool
 	isCatchAll bool
+	// The constraint values must satisfy to match this wildcard node, if any.
+	constraint *constraint
 	// If true, the head handler was set implicitly, so let it also be set explicitly.
 	implicitHead bool
 	// If this node is the end of the URL, then call the handler, if applicable.
 	leafHandler map[string]HandlerFunc
+	// The routes registered at this node, keyed by method like leafHandler.
+	leafRoute map[string]*Route
 
 	// The names of the parameters to apply.
 	leafWildcardNames []string
+
+	// The methods with handlers for the Allow header, and the same with OPTIONS
+	// for routers answering it implicitly.
+	allowed            allowedMethods
+	allowedWithOptions allowedMethods
+}
+
+// allowedMethods are the sorted methods of a node along with their Allow header.
+type allowedMethods struct {
+	methods []string
+	header  string
+}
+
+func newAllowedMethods(methods []string) allowedMethods {
+	sort.Strings(methods)
+
+	return allowedMethods{methods: methods, header: strings.Join(methods, ", ")}
+}
+
+// updateAllowed recomputes the allowed methods after the handlers of n changed.
+func (n *node) updateAllowed() {
+	methods := make([]string, 0, len(n.leafHandler)+1)
+	for method := range n.leafHandler {
+		methods = append(methods, method)
+	}
+
+	n.allowed = newAllowedMethods(methods)
+	if _, ok := n.leafHandler[http.MethodOptions]; ok {
+		n.allowedWithOptions = n.allowed
+		return
+	}
+
+	n.allowedWithOptions = newAllowedMethods(append(methods[:len(methods):len(methods)], http.MethodOptions))
+}
+
+// clone returns a shallow copy of n, whose children and handlers can be replaced without
+// affecting n, as n may be in use by concurrent lookups.
+func (n *node) clone() *node {
+	c := *n
+	c.staticIndices = append([]byte(nil), n.staticIndices...)
+	c.staticChild = append([]*node(nil), n.staticChild...)
+	c.wildcardChildren = append([]*node(nil), n.wildcardChildren...)
+
+	if n.leafHandler != nil {
+		c.leafHandler = make(map[string]HandlerFunc, len(n.leafHandler))
+		for verb, handler := range n.leafHandler {
+			c.leafHandler[verb] = handler
+		}
+
+		c.leafRoute = make(map[string]*Route, len(n.leafRoute))
+		for verb, route := range n.leafRoute {
+			c.leafRoute[verb] = route
+		}
+	}
+
+	return &c
 }
 
 func (n *node) sortStaticChild(i int) {
@@ -43,35 +105,73 @@ func (n *node) sortStaticChild(i int) {
 	}
 }
 
-func (n *node) setHandler(verb string, handler HandlerFunc, implicitHead bool) {
+func (n *node) setHandler(verb string, handler HandlerFunc, route *Route, implicitHead bool) error {
 	if n.leafHandler == nil {
 		n.leafHandler = make(map[string]HandlerFunc)
+		n.leafRoute = make(map[string]*Route)
 	}
 	_, ok := n.leafHandler[verb]
+	if ok && verb == "HEAD" && implicitHead {
+		// Keep the explicitly set head handler.
+		return nil
+	}
 	if ok && (verb != "HEAD" || !n.implicitHead) {
-		panic(fmt.Sprintf("%s already handles %s", n.path, verb))
+		return &DuplicateRouteError{Method: verb, Path: route.path, Existing: n.leafRoute[verb].path}
 	}
 	n.leafHandler[verb] = handler
+	n.leafRoute[verb] = route
+	n.updateAllowed()
 
 	if verb == "HEAD" {
 		n.implicitHead = implicitHead
 	}
+
+	return nil
 }
 
-func (n *node) registerPath(method, path string, handler HandlerFunc, redirectTrailingSlash bool) {
+// registerPath registers handler for the method and path of route. If head is not nil,
+// it's implicitly registered for HEAD, unless HEAD is registered explicitly.
+func (n *node) registerPath(route *Route, handler, head HandlerFunc, redirectTrailingSlash bool) error {
+	path := route.path
 	addSlash := false
 	if len(path) > 1 && path[len(path)-1] == '/' && redirectTrailingSlash {
 		addSlash = true
 		path = path[:len(path)-1]
 	}
 
-	node := n.addPath(path[1:], nil, false)
+	node, err := n.addPath(path[1:], nil, false)
+	switch err := err.(type) {
+	case nil:
+	case *InvalidPathError:
+		err.Path = route.path
+		return err
+	case *WildcardConflictError:
+		err.Path = route.path
+		return err
+	default:
+		return err
+	}
+
+	if err := node.setHandler(route.method, handler, route, false); err != nil {
+		return err
+	}
+
+	if head != nil {
+		if err := node.setHandler("HEAD", head, route, true); err != nil {
+			return err
+		}
+	}
+
 	node.route = path
 	node.addSlash = addSlash
-	node.setHandler(method, handler, false)
+
+	return nil
 }
 
-func (n *node) addPath(path string, wildcards []string, inStaticToken bool) *node {
+// addPath adds the nodes for path below n and returns the leaf node. Existing nodes on the
+// way are replaced with clones, so n must not be in use by lookups, while its descendants may.
+// Errors returned from addPath don't have their Path set, as only the remaining path is known here.
+func (n *node) addPath(path string, wildcards []string, inStaticToken bool) (*node, error) {
 	leaf := len(path) == 0
 	if leaf {
 		if wildcards != nil {
@@ -85,8 +185,7 @@ func (n *node) addPath(path string, wildcards []string, inStaticToken bool) *nod
 
 				for i := 0; i < len(wildcards); i++ {
 					if n.leafWildcardNames[i] != wildcards[i] {
-						panic(fmt.Sprintf("Wildcards %v are ambiguous with wildcards %v",
-							n.leafWildcardNames, wildcards))
+						return nil, &WildcardConflictError{Existing: n.route}
 					}
 				}
 			} else {
@@ -95,7 +194,7 @@ func (n *node) addPath(path string, wildcards []string, inStaticToken bool) *nod
 			}
 		}
 
-		return n
+		return n, nil
 	}
 
 	c := path[0]
@@ -121,15 +220,17 @@ func (n *node) addPath(path string, wildcards []string, inStaticToken bool) *nod
 		thisToken = thisToken[1:]
 		if n.catchAllChild == nil {
 			n.catchAllChild = &node{path: thisToken, isCatchAll: true}
+		} else {
+			n.catchAllChild = n.catchAllChild.clone()
 		}
 
-		if path[1:] != n.catchAllChild.path {
-			panic(fmt.Sprintf("Catch-all name in %s doesn't match %s. You probably tried to define overlapping catchalls",
-				path, n.catchAllChild.path))
+		if nextSlash != -1 {
+			return nil, &InvalidPathError{Reason: "/ after catch-all"}
 		}
 
-		if nextSlash != -1 {
-			panic("/ after catch-all found in " + path)
+		if path[1:] != n.catchAllChild.path {
+			// Overlapping catch-alls with different names.
+			return nil, &WildcardConflictError{Existing: n.catchAllChild.route}
 		}
 
 		if wildcards == nil {
@@ -137,24 +238,27 @@ func (n *node) addPath(path string, wildcards []string, inStaticToken bool) *nod
 		} else {
 			wildcards = append(wildcards, thisToken)
 		}
-		n.catchAllChild.leafWildcardNames = wildcards
 
-		return n.catchAllChild
+		return n.catchAllChild.addPath("", wildcards, false)
 	} else if c == ':' && !inStaticToken {
-		// Token starts with a :
-		thisToken = thisToken[1:]
+		// Token starts with a :, optionally followed by a <constraint>
+		name, expr, end, ok := splitParam(path)
+		if !ok {
+			return nil, &InvalidPathError{Reason: "parameter constraint must be closed at the end of the segment"}
+		}
 
 		if wildcards == nil {
-			wildcards = []string{thisToken}
+			wildcards = []string{name}
 		} else {
-			wildcards = append(wildcards, thisToken)
+			wildcards = append(wildcards, name)
 		}
 
-		if n.wildcardChild == nil {
-			n.wildcardChild = &node{path: "wildcard"}
+		child, err := n.addWildcardChild(expr)
+		if err != nil {
+			return nil, err
 		}
 
-		return n.wildcardChild.addPath(remainingPath, wildcards, false)
+		return child.addPath(path[end:], wildcards, false)
 
 	} else {
 		// if strings.ContainsAny(thisToken, ":*") {
@@ -206,8 +310,146 @@ func (n *node) addPath(path string, wildcards []string, inStaticToken bool) *nod
 	}
 }
 
+func (n *node) addWildcardChild(expr string) (*node, error) {
+	for i, child := range n.wildcardChildren {
+		if child.matchesExpr(expr) {
+			child = child.clone()
+			n.wildcardChildren[i] = child
+
+			return child, nil
+		}
+	}
+
+	if expr == "" {
+		child := &node{path: "wildcard"}
+		n.wildcardChildren = append(n.wildcardChildren, child)
+
+		return child, nil
+	}
+
+	c, err := newConstraint(expr)
+	if err != nil {
+		return nil, &InvalidPathError{Reason: fmt.Sprintf("invalid parameter constraint %q: %v", expr, err)}
+	}
+
+	child := &node{path: "<" + expr + ">", constraint: c}
+
+	// Insert before the unconstrained child, if any, so it's matched last.
+	i := len(n.wildcardChildren)
+	if i > 0 && n.wildcardChildren[i-1].constraint == nil {
+		i--
+	}
+
+	n.wildcardChildren = append(n.wildcardChildren, nil)
+	copy(n.wildcardChildren[i+1:], n.wildcardChildren[i:])
+	n.wildcardChildren[i] = child
+
+	return child, nil
+}
+
+// matchesExpr reports whether n is the wildcard child for constraint expr, or the
+// unconstrained one if expr is empty.
+func (n *node) matchesExpr(expr string) bool {
+	if n.constraint == nil {
+		return expr == ""
+	}
+
+	return n.constraint.expr == expr
+}
+
+// findPath returns the node registered for path below n along with its parent, or nil if
+// there is none. Like addPath, it replaces the nodes on the way with clones.
+func (n *node) findPath(path string, inStaticToken bool) (found, parent *node) {
+	if len(path) == 0 {
+		return n, nil
+	}
+
+	var child *node
+	var rest string
+
+	c := path[0]
+	switch {
+	case c == '*' && !inStaticToken:
+		if n.catchAllChild == nil || n.catchAllChild.path != path[1:] {
+			return nil, nil
+		}
+
+		n.catchAllChild = n.catchAllChild.clone()
+
+		return n.catchAllChild, n
+	case c == ':' && !inStaticToken:
+		_, expr, end, ok := splitParam(path)
+		if !ok {
+			return nil, nil
+		}
+
+		for i, wildcardChild := range n.wildcardChildren {
+			if wildcardChild.matchesExpr(expr) {
+				child = wildcardChild.clone()
+				n.wildcardChildren[i] = child
+				rest = path[end:]
+				inStaticToken = false
+				break
+			}
+		}
+	default:
+		if len(path) >= 2 && !inStaticToken && c == '\\' && (path[1] == '*' || path[1] == ':' || path[1] == '\\') {
+			// Drop the backslash escaping the first character like addPath.
+			path = path[1:]
+			c = path[0]
+		}
+
+		inStaticToken = c != '/'
+		for i, index := range n.staticIndices {
+			if index == c && strings.HasPrefix(path, n.staticChild[i].path) {
+				child = n.staticChild[i].clone()
+				n.staticChild[i] = child
+				rest = path[len(child.path):]
+				break
+			}
+		}
+	}
+
+	if child == nil {
+		return nil, nil
+	}
+
+	found, parent = child.findPath(rest, inStaticToken)
+	if found == child {
+		parent = n
+	}
+
+	return found, parent
+}
+
+// removeHandler removes the handler for verb, along with the implicit head handler of GET.
+func (n *node) removeHandler(verb string) {
+	route := n.leafRoute[verb]
+	delete(n.leafHandler, verb)
+	delete(n.leafRoute, verb)
+
+	switch {
+	case verb == http.MethodGet && n.implicitHead && n.leafRoute[http.MethodHead] == route:
+		delete(n.leafHandler, http.MethodHead)
+		delete(n.leafRoute, http.MethodHead)
+		n.implicitHead = false
+	case verb == http.MethodHead:
+		n.implicitHead = false
+	}
+
+	n.updateAllowed()
+
+	if len(n.leafHandler) == 0 {
+		// Let routes with other parameter names be registered here.
+		n.leafWildcardNames = nil
+		n.route = ""
+		n.addSlash = false
+	}
+}
+
 func (n *node) splitCommonPrefix(existingNodeIndex int, path string) (*node, int) {
-	childNode := n.staticChild[existingNodeIndex]
+	childNode := n.staticChild[existingNodeIndex].clone()
+	n.staticChild[existingNodeIndex] = childNode
 
 	if strings.HasPrefix(path, childNode.path) {
 		// No split needs to be done. Rather, the new path shares the entire
@@ -246,19 +488,25 @@ func (n *node) splitCommonPrefix(existingNodeIndex int, path string) (*node, int
 	return newNode, i
 }
 
-func (n *node) search(method, path string) (found *node, handler HandlerFunc, params []string) {
+// search looks up the node matching path, appending the values of its wildcards to params
+// in reverse order, i.e. the value of the last wildcard first. On return params holds
+// exactly the values for the returned node past its length on entry. Tokens of an escaped
+// path are unescaped for constraints if rawPath is set.
+func (n *node) search(method, path string, params *Params, rawPath bool) (found *node, handler HandlerFunc) {
 	// if test != nil {
 	// 	test.Logf("Searching for %s in %s", path, n.dumpTree("", ""))
 	// }
 	pathLen := len(path)
 	if pathLen == 0 {
 		if len(n.leafHandler) == 0 {
-			return nil, nil, nil
+			return nil, nil
 		} else {
-			return n, n.leafHandler[method], nil
+			return n, n.leafHandler[method]
 		}
 	}
 
+	base := len(*params)
+
 	// First see if this matches a static token.
 	firstChar := path[0]
 	for i, staticIndex := range n.staticIndices {
@@ -267,7 +515,7 @@ func (n *node) search(method, path string) (found *node, handler HandlerFunc, pa
 			childPathLen := len(child.path)
 			if pathLen >= childPathLen && child.path == path[:childPathLen] {
 				nextPath := path[childPathLen:]
-				found, handler, params = child.search(method, nextPath)
+				found, handler = child.search(method, nextPath, params, rawPath)
 			}
 			break
 		}
@@ -279,7 +527,11 @@ func (n *node) search(method, path string) (found *node, handler HandlerFunc, pa
 		return
 	}
 
-	if n.wildcardChild != nil {
+	// The values of the node found so far, if any, are kept in params[base:foundEnd]
+	// while looking for a better match, which appends its values after them.
+	foundEnd := len(*params)
+
+	if len(n.wildcardChildren) != 0 {
 		// Didn't find a static token, so check for a wildcard.
 		nextSlash := strings.IndexByte(path, '/')
 		if nextSlash < 0 {
@@ -290,52 +542,62 @@ func (n *node) search(method, path string) (found *node, handler HandlerFunc, pa
 		nextToken := path[nextSlash:]
 
 		if len(thisToken) > 0 { // Don't match on empty tokens.
-			wcNode, wcHandler, wcParams := n.wildcardChild.search(method, nextToken)
-			if wcHandler != nil || (found == nil && wcNode != nil) {
-				unescaped, err := url.QueryUnescape(thisToken)
-				if err != nil {
-					unescaped = thisToken
+			for _, wildcardChild := range n.wildcardChildren {
+				if wildcardChild.constraint != nil && !wildcardChild.constraint.matchToken(thisToken, rawPath) {
+					continue
 				}
 
-				if wcParams == nil {
-					wcParams = []string{unescaped}
-				} else {
-					wcParams = append(wcParams, unescaped)
-				}
+				wcNode, wcHandler := wildcardChild.search(method, nextToken, params, rawPath)
+				if wcHandler != nil || (found == nil && wcNode != nil) {
+					*params = append(*params, Parameter{Value: thisToken})
 
-				if wcHandler != nil {
-					return wcNode, wcHandler, wcParams
-				}
+					if wcHandler != nil {
+						// Drop the values of the node found before.
+						*params = (*params)[:base+copy((*params)[base:], (*params)[foundEnd:])]
+						return wcNode, wcHandler
+					}
 
-				// Didn't actually find a handler here, so remember that we
-				// found a node but also see if we can fall through to the
-				// catchall.
-				found = wcNode
-				handler = wcHandler
-				params = wcParams
+					// Didn't actually find a handler here, so remember that we
+					// found a node but also see if we can fall through to the
+					// catchall.
+					found = wcNode
+					handler = wcHandler
+					foundEnd = len(*params)
+				} else {
+					*params = (*params)[:foundEnd]
+				}
 			}
 		}
 	}
 
 	catchAllChild := n.catchAllChild
-	if catchAllChild != nil {
+	if catchAllChild != nil && len(catchAllChild.leafHandler) != 0 {
 		// Hit the catchall, so just assign the whole remaining path if it
 		// has a matching handler.
-		handler = catchAllChild.leafHandler[method]
+		catchAllHandler := catchAllChild.leafHandler[method]
 		// Found a handler, or we found a catchall node without a handler.
 		// Either way, return it since there's nothing left to check after this.
-		if handler != nil || found == nil {
-			unescaped, err := url.QueryUnescape(path)
-			if err != nil {
-				unescaped = path
-			}
-
-			return catchAllChild, handler, []string{unescaped}
+		if catchAllHandler != nil || found == nil {
+			*params = append((*params)[:base], Parameter{Value: path})
+			return catchAllChild, catchAllHandler
 		}
 
 	}
 
-	return found, handler, params
+	return found, handler
+}
+
+func (n *node) walk(fn func(n *node)) {
+	fn(n)
+	for _, child := range n.staticChild {
+		child.walk(fn)
+	}
+	for _, child := range n.wildcardChildren {
+		child.walk(fn)
+	}
+	if n.catchAllChild != nil {
+		n.catchAllChild.walk(fn)
+	}
 }
 
 func (n *node) dumpTree(prefix, nodeType string) string {
@@ -345,8 +607,8 @@ func (n *node) dumpTree(prefix, nodeType string) string {
 	for _, node := range n.staticChild {
 		line += node.dumpTree(prefix, "")
 	}
-	if n.wildcardChild != nil {
-		line += n.wildcardChild.dumpTree(prefix, ":")
+	for _, child := range n.wildcardChildren {
+		line += child.dumpTree(prefix, ":")
 	}
 	if n.catchAllChild != nil {
 		line += n.catchAllChild.dumpTree(prefix, "*")
@@ -1,6 +1,6 @@
 module github.com/goes-funky/httprouter/zapdriver
 
-go 1.17
+go 1.18
 
 require (
 	github.com/goes-funky/httprouter v0.0.0-20211118180036-82957f41fe1a